@@ -0,0 +1,457 @@
+package validate
+
+import (
+	"context"
+	"fmt"
+	"github.com/mbict/go-errors"
+	"github.com/mbict/go-tags"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// Rules is a set of validators for values of type T created with the rule
+// builders, e.g. String().Required().Min(3). The validators are the same as the
+// ones used in the struct tags.
+type Rules[T any] interface {
+	// Tag returns the validators in the struct tag format
+	Tag() string
+	// Field returns the rules as the FieldRule for the field of a structure
+	Field(name string) FieldRule
+	// Compile compiles the rules with the validators registered on the validator
+	Compile(v Validator) (*CompiledRules[T], error)
+	params() []tags.Param
+	defaultRules() (*CompiledRules[T], error)
+}
+
+// ErrEachUnsupported is the error returned when rules holding the rules of the
+// elements, see SliceRules.Each, are used to validate a single value
+var ErrEachUnsupported = errors.New("rules with Each can only validate the field of a structure")
+
+// Number is the constraint of the numeric types the rule builders accept
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 |
+		~float32 | ~float64
+}
+
+// ruleSet holds the validators added by a rule builder
+type ruleSet[T any] struct {
+	validators []tags.Param
+	compiled   atomic.Value // *compiledRuleSet[T] for the last configuration of the default validator
+}
+
+// compiledRuleSet is the rule set compiled for a configuration
+type compiledRuleSet[T any] struct {
+	cfg   *config
+	rules *CompiledRules[T]
+	err   error
+}
+
+func (r *ruleSet[T]) add(name string, args ...string) {
+	r.validators = append(r.validators, tags.Param{Name: name, Args: args})
+	r.compiled = atomic.Value{}
+}
+
+func (r *ruleSet[T]) params() []tags.Param {
+	return r.validators
+}
+
+// Tag returns the validators in the struct tag format
+func (r *ruleSet[T]) Tag() string {
+	return formatTag(r.validators)
+}
+
+// Field returns the rules as the FieldRule for the field of a structure, the
+// validators are appended to the struct tag of the field
+func (r *ruleSet[T]) Field(name string) FieldRule {
+	return FieldRule{Field: name, Tag: r.Tag()}
+}
+
+// Compile compiles the rules with the validators registered on the validator,
+// an unknown validator or bad parameter fails the compilation
+func (r *ruleSet[T]) Compile(v Validator) (*CompiledRules[T], error) {
+	mv, ok := v.(*validator)
+	if !ok {
+		return nil, ErrUnsupported
+	}
+	return r.compile(mv.config())
+}
+
+// defaultRules returns the rules compiled for the current configuration of the
+// default validator, the rules are compiled again when the configuration changed
+func (r *ruleSet[T]) defaultRules() (*CompiledRules[T], error) {
+	cfg := defaultValidator.(*validator).config()
+	if compiled, ok := r.compiled.Load().(*compiledRuleSet[T]); ok && compiled.cfg == cfg {
+		return compiled.rules, compiled.err
+	}
+
+	rules, err := r.compile(cfg)
+	r.compiled.Store(&compiledRuleSet[T]{cfg: cfg, rules: rules, err: err})
+	return rules, err
+}
+
+// compile compiles the rules with the configuration, the params are checked
+// against the type T. Rules of the elements are rejected with ErrEachUnsupported,
+// they only run for the fields of a structure.
+func (r *ruleSet[T]) compile(cfg *config) (*CompiledRules[T], error) {
+	ft, err := cfg.compileFieldTags(r.validators)
+	if err != nil {
+		return nil, err
+	}
+
+	if ft.elem != nil {
+		return nil, ErrEachUnsupported
+	}
+
+	if err := cfg.checkParams(ft.validators, reflect.TypeOf((*T)(nil)).Elem()); err != nil {
+		return nil, err
+	}
+
+	return &CompiledRules[T]{
+		tag:        r.Tag(),
+		validators: ft.validators,
+	}, nil
+}
+
+// formatTag formats the params in the struct tag format
+func formatTag(params []tags.Param) string {
+	validators := make([]string, len(params))
+	for i, p := range params {
+		validators[i] = p.Name
+		if len(p.Args) > 0 {
+			args := make([]string, len(p.Args))
+			for j, arg := range p.Args {
+				args[j] = quoteArg(arg)
+			}
+			validators[i] += "(" + strings.Join(args, ",") + ")"
+		}
+	}
+	return strings.Join(validators, ";")
+}
+
+// quoteArg quotes the argument when it holds characters of the tag grammar
+func quoteArg(arg string) string {
+	if arg == strings.TrimSpace(arg) && !strings.ContainsAny(arg, `,;()'"\`) {
+		return arg
+	}
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(arg) + "'"
+}
+
+// StringRules builds the validators for a string
+type StringRules struct {
+	ruleSet[string]
+}
+
+// String starts the rules for a string
+func String() *StringRules {
+	return &StringRules{}
+}
+
+// Required fails on an empty string
+func (r *StringRules) Required() *StringRules {
+	r.add("required")
+	return r
+}
+
+// OmitEmpty skips the remaining validators for an empty string
+func (r *StringRules) OmitEmpty() *StringRules {
+	r.add("omitempty")
+	return r
+}
+
+// Len checks the length of the string
+func (r *StringRules) Len(n int) *StringRules {
+	r.add("len", strconv.Itoa(n))
+	return r
+}
+
+// Min checks the minimum length of the string
+func (r *StringRules) Min(n int) *StringRules {
+	r.add("min", strconv.Itoa(n))
+	return r
+}
+
+// Max checks the maximum length of the string
+func (r *StringRules) Max(n int) *StringRules {
+	r.add("max", strconv.Itoa(n))
+	return r
+}
+
+// Between checks the length of the string is within the bounds
+func (r *StringRules) Between(min, max int) *StringRules {
+	r.add("between", strconv.Itoa(min), strconv.Itoa(max))
+	return r
+}
+
+// Regexp checks the string matches the regular expression, it panics when the
+// pattern does not compile like regexp.MustCompile
+func (r *StringRules) Regexp(pattern string) *StringRules {
+	if _, err := regexp.Compile(pattern); err != nil {
+		panic(fmt.Sprintf("validate: Regexp(%q): %v", pattern, err))
+	}
+	r.add("regexp", pattern)
+	return r
+}
+
+// Email checks the string is an email address
+func (r *StringRules) Email() *StringRules {
+	r.add("email")
+	return r
+}
+
+// URL checks the string is an url
+func (r *StringRules) URL() *StringRules {
+	r.add("url")
+	return r
+}
+
+// UUID checks the string is an uuid
+func (r *StringRules) UUID() *StringRules {
+	r.add("uuid")
+	return r
+}
+
+// In checks the string is one of the values
+func (r *StringRules) In(values ...string) *StringRules {
+	r.add("in", values...)
+	return r
+}
+
+// Exclude checks the string is none of the values
+func (r *StringRules) Exclude(values ...string) *StringRules {
+	r.add("exclude", values...)
+	return r
+}
+
+// Custom adds a validator registered by name, like the validators set with SetValidationFunc
+func (r *StringRules) Custom(name string, params ...string) *StringRules {
+	r.add(name, params...)
+	return r
+}
+
+// NumberRules builds the validators for a number
+type NumberRules[T Number] struct {
+	ruleSet[T]
+}
+
+// NumberOf starts the rules for a number of type T
+func NumberOf[T Number]() *NumberRules[T] {
+	return &NumberRules[T]{}
+}
+
+// Int starts the rules for an int
+func Int() *NumberRules[int] {
+	return NumberOf[int]()
+}
+
+// Int64 starts the rules for an int64
+func Int64() *NumberRules[int64] {
+	return NumberOf[int64]()
+}
+
+// Uint starts the rules for an uint
+func Uint() *NumberRules[uint] {
+	return NumberOf[uint]()
+}
+
+// Float64 starts the rules for a float64
+func Float64() *NumberRules[float64] {
+	return NumberOf[float64]()
+}
+
+// Required fails on zero
+func (r *NumberRules[T]) Required() *NumberRules[T] {
+	r.add("required")
+	return r
+}
+
+// OmitEmpty skips the remaining validators for zero
+func (r *NumberRules[T]) OmitEmpty() *NumberRules[T] {
+	r.add("omitempty")
+	return r
+}
+
+// Min checks the number is not less than min
+func (r *NumberRules[T]) Min(min T) *NumberRules[T] {
+	r.add("min", formatNumber(min))
+	return r
+}
+
+// Max checks the number is not greater than max
+func (r *NumberRules[T]) Max(max T) *NumberRules[T] {
+	r.add("max", formatNumber(max))
+	return r
+}
+
+// Between checks the number is within the bounds
+func (r *NumberRules[T]) Between(min, max T) *NumberRules[T] {
+	r.add("between", formatNumber(min), formatNumber(max))
+	return r
+}
+
+// Around checks the number is outside the bounds
+func (r *NumberRules[T]) Around(min, max T) *NumberRules[T] {
+	r.add("around", formatNumber(min), formatNumber(max))
+	return r
+}
+
+// In checks the number is one of the values
+func (r *NumberRules[T]) In(values ...T) *NumberRules[T] {
+	r.add("in", formatNumbers(values)...)
+	return r
+}
+
+// Exclude checks the number is none of the values
+func (r *NumberRules[T]) Exclude(values ...T) *NumberRules[T] {
+	r.add("exclude", formatNumbers(values)...)
+	return r
+}
+
+// Custom adds a validator registered by name, like the validators set with SetValidationFunc
+func (r *NumberRules[T]) Custom(name string, params ...string) *NumberRules[T] {
+	r.add(name, params...)
+	return r
+}
+
+// SliceRules builds the validators for a slice
+type SliceRules[E any] struct {
+	ruleSet[[]E]
+}
+
+// Slice starts the rules for a slice with elements of type E
+func Slice[E any]() *SliceRules[E] {
+	return &SliceRules[E]{}
+}
+
+// Required fails on an empty slice
+func (r *SliceRules[E]) Required() *SliceRules[E] {
+	r.add("required")
+	return r
+}
+
+// OmitEmpty skips the remaining validators for an empty slice
+func (r *SliceRules[E]) OmitEmpty() *SliceRules[E] {
+	r.add("omitempty")
+	return r
+}
+
+// Len checks the number of elements
+func (r *SliceRules[E]) Len(n int) *SliceRules[E] {
+	r.add("len", strconv.Itoa(n))
+	return r
+}
+
+// Min checks the minimum number of elements
+func (r *SliceRules[E]) Min(n int) *SliceRules[E] {
+	r.add("min", strconv.Itoa(n))
+	return r
+}
+
+// Max checks the maximum number of elements
+func (r *SliceRules[E]) Max(n int) *SliceRules[E] {
+	r.add("max", strconv.Itoa(n))
+	return r
+}
+
+// Custom adds a validator registered by name, like the validators set with SetValidationFunc
+func (r *SliceRules[E]) Custom(name string, params ...string) *SliceRules[E] {
+	r.add(name, params...)
+	return r
+}
+
+// Each applies the rules to every element of the slice and ends the rules of the
+// slice. Like a dive in a struct tag the elements are only validated as a field
+// of a structure, see SetFieldRules. Compile, ValidRules and ValidAllRules return
+// ErrEachUnsupported for these rules.
+func (r *SliceRules[E]) Each(elem Rules[E]) Rules[[]E] {
+	each := &ruleSet[[]E]{}
+	each.validators = append(append(each.validators, r.validators...), tags.Param{Name: diveTag})
+	each.validators = append(each.validators, elem.params()...)
+	return each
+}
+
+// formatNumber formats the number as a parameter
+func formatNumber[T Number](n T) string {
+	v := reflect.ValueOf(n)
+	switch v.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, 64)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10)
+	}
+	return strconv.FormatInt(v.Int(), 10)
+}
+
+// formatNumbers formats the numbers as parameters
+func formatNumbers[T Number](values []T) []string {
+	params := make([]string, len(values))
+	for i, v := range values {
+		params[i] = formatNumber(v)
+	}
+	return params
+}
+
+// ValidRules validates the value with the rules of the default validator and
+// returns the first validation error found. The rules are compiled once for the
+// configuration of the default validator.
+func ValidRules[T any, R Rules[T]](v T, rules R) error {
+	compiled, err := rules.defaultRules()
+	if err != nil {
+		return err
+	}
+	return compiled.Valid(v)
+}
+
+// ValidAllRules validates the value with the rules of the default validator and
+// returns the validation errors found
+func ValidAllRules[T any, R Rules[T]](v T, rules R) error {
+	compiled, err := rules.defaultRules()
+	if err != nil {
+		return err
+	}
+	return compiled.ValidAll(v)
+}
+
+// CompiledRules holds rules compiled once by a validator, see Rules.Compile.
+// Values are validated without parsing the rules again.
+type CompiledRules[T any] struct {
+	tag        string
+	validators []validatorTag
+}
+
+// Valid validates the value and returns the first validation error found
+func (c *CompiledRules[T]) Valid(v T) error {
+	return c.valid(context.Background(), v, true)
+}
+
+// ValidCtx validates the value like Valid and passes the context to the context aware validators
+func (c *CompiledRules[T]) ValidCtx(ctx context.Context, v T) error {
+	return c.valid(ctx, v, true)
+}
+
+// ValidAll validates the value and returns the validation errors found
+func (c *CompiledRules[T]) ValidAll(v T) error {
+	return c.valid(context.Background(), v, false)
+}
+
+// ValidAllCtx validates the value like ValidAll and passes the context to the context aware validators
+func (c *CompiledRules[T]) ValidAllCtx(ctx context.Context, v T) error {
+	return c.valid(ctx, v, false)
+}
+
+func (c *CompiledRules[T]) valid(ctx context.Context, v T, stopOnError bool) error {
+	var val interface{} = v
+	rv := reflect.ValueOf(val)
+	for rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+		val = rv.Interface()
+	}
+
+	if rv.Kind() == reflect.Invalid {
+		val = nil
+	}
+	return runVar(ctx, val, c.tag, c.validators, stopOnError)
+}
@@ -0,0 +1,92 @@
+package validate_test
+
+import (
+	"github.com/mbict/go-tags"
+	"github.com/mbict/go-validate"
+	. "gopkg.in/check.v1"
+)
+
+type BuilderSuite struct{}
+
+var _ = Suite(&BuilderSuite{})
+
+func (s *BuilderSuite) TestTag(c *C) {
+	c.Assert(validate.String().Required().Min(3).Max(6).Email().Tag(), Equals, "required;min(3);max(6);email")
+	c.Assert(validate.String().Regexp(`^(a|b),c$`).Tag(), Equals, `regexp('^(a|b),c$')`)
+	c.Assert(validate.Int().Between(1, 10).In(1, 5).Tag(), Equals, "between(1,10);in(1,5)")
+	c.Assert(validate.Float64().Around(-1.5, 2).Tag(), Equals, "around(-1.5,2)")
+	c.Assert(validate.Slice[string]().Min(1).Each(validate.String().Len(2)).Tag(), Equals, "min(1);dive;len(2)")
+}
+
+func (s *BuilderSuite) TestTagParsesQuotedArgs(c *C) {
+	params, err := tags.Parse(validate.String().Regexp(`^a,(b)'c$`).In(`x;y`, `\z`).Tag())
+	c.Assert(err, IsNil)
+	c.Assert(params, DeepEquals, []tags.Param{
+		{Name: "regexp", Args: []string{`^a,(b)'c$`}},
+		{Name: "in", Args: []string{`x;y`, `\z`}},
+	})
+}
+
+func (s *BuilderSuite) TestRegexpPanicsOnBadPattern(c *C) {
+	c.Assert(func() { validate.String().Regexp(`^(a`) }, PanicMatches, `validate: Regexp\("\^\(a"\): .*`)
+}
+
+func (s *BuilderSuite) TestValidRules(c *C) {
+	rules := validate.String().Required().Min(3)
+	c.Assert(validate.ValidRules("abcd", rules), IsNil)
+	c.Assert(validate.ValidRules("ab", rules), HasError, validate.ErrMin)
+	c.Assert(validate.ValidAllRules("", rules), HasError, validate.ErrRequired)
+	c.Assert(validate.ValidRules("abcd", rules.Max(3)), HasError, validate.ErrMax)
+
+	c.Assert(validate.ValidRules(11, validate.Int().Between(1, 10)), HasError, validate.ErrBetween)
+	c.Assert(validate.ValidRules("x-y", validate.String().Regexp(`^\w+(-\w+)?$`)), IsNil)
+	c.Assert(validate.ValidRules([]string{"x"}, validate.Slice[string]().Min(1).Each(validate.String().Len(2))), Equals, validate.ErrEachUnsupported)
+
+	c.Assert(validate.ValidRules("a", validate.String().Custom("unknown")), Equals, validate.ErrUnknownTag)
+	c.Assert(validate.ValidRules(uint(1), validate.Uint().Custom("min", "-1")), Equals, validate.ErrBadParameter)
+	c.Assert(validate.ValidAllRules(1, validate.Int().Custom("max", "1.5")), Equals, validate.ErrBadParameter)
+}
+
+func (s *BuilderSuite) TestCompile(c *C) {
+	validator := validate.NewValidator()
+	validator.SetValidationFunc("even", func(v interface{}, params []string) error {
+		if v.(int)%2 != 0 {
+			return validate.ErrInvalid
+		}
+		return nil
+	})
+
+	compiled, err := validate.Int().Min(2).Custom("even").Compile(validator)
+	c.Assert(err, IsNil)
+	c.Assert(compiled.Valid(4), IsNil)
+	c.Assert(compiled.Valid(3), HasError, validate.ErrInvalid)
+	c.Assert(compiled.ValidAll(1), HasLen, 2)
+
+	_, err = validate.String().Custom("unknown").Compile(validator)
+	c.Assert(err, Equals, validate.ErrUnknownTag)
+
+	_, err = validate.Slice[int]().Each(validate.Int().Min(1)).Compile(validator)
+	c.Assert(err, Equals, validate.ErrEachUnsupported)
+}
+
+func (s *BuilderSuite) TestFieldRules(c *C) {
+	type order struct {
+		Reference string
+		Lines     []string
+	}
+
+	validator := validate.NewValidator()
+	err := validator.SetFieldRules(order{},
+		validate.String().Required().Len(8).Field("Reference"),
+		validate.Slice[string]().Required().Each(validate.String().Min(2)).Field("Lines"),
+	)
+	c.Assert(err, IsNil)
+
+	err = validator.ValidateAll(order{Reference: "abc", Lines: []string{"ok", "x"}})
+	c.Assert(err, NotNil)
+
+	errs := err.(validate.Errors)
+	c.Assert(errs, HasLen, 2)
+	c.Assert(errs["Reference"], HasError, validate.ErrLen)
+	c.Assert(errs["Lines.1"], HasError, validate.ErrMin)
+}
@@ -6,6 +6,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // omitempty tests whether a variable is zero
@@ -95,388 +96,266 @@ func notEmpty(i interface{}, params []string) error {
 	return nil
 }
 
-// length tests whether a variable's length is equal to a given
-// value. For strings it tests the number of characters whereas
-// for maps and slices it tests the number of items.
-func length(v interface{}, params []string) error {
-	if len(params) != 1 {
-		return ErrInvalidParameterCount
+// compileLength compiles the len validator that tests whether a variable's
+// length is equal to a given value. For strings it tests the number of
+// characters whereas for maps and slices it tests the number of items.
+func compileLength(params []string) (ValidatorFunc, error) {
+	p, err := parseNumberParams(params, 1)
+	if err != nil {
+		return nil, err
 	}
 
-	st := reflect.ValueOf(v)
-	valid := true
-	switch st.Kind() {
-	case reflect.String:
-		p, err := asInt(params[0])
-		if err != nil {
-			return ErrBadParameter
-		}
-		valid = int64(len(st.String())) == p
-	case reflect.Slice, reflect.Map, reflect.Array:
-		p, err := asInt(params[0])
+	return func(v interface{}, _ []string) error {
+		c, err := compareNumber(v, p[0])
 		if err != nil {
-			return ErrBadParameter
+			return err
 		}
-		valid = int64(st.Len()) == p
-	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		p, err := asInt(params[0])
-		if err != nil {
-			return ErrBadParameter
-		}
-		valid = st.Int() == p
-	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
-		p, err := asUint(params[0])
-		if err != nil {
-			return ErrBadParameter
-		}
-		valid = st.Uint() == p
-	case reflect.Float32, reflect.Float64:
-		p, err := asFloat(params[0])
-		if err != nil {
-			return ErrBadParameter
+		if c != 0 {
+			return ErrLen
 		}
-		valid = st.Float() == p
-	default:
-		return ErrUnsupported
-	}
-	if !valid {
-		return ErrLen
-	}
-	return nil
+		return nil
+	}, nil
 }
 
-// min tests whether a variable value is larger or equal to a given
-// number. For number types, it's a simple lesser-than test; for
-// strings it tests the number of characters whereas for maps
-// and slices it tests the number of items.
-func min(v interface{}, params []string) error {
-	if len(params) != 1 {
-		return ErrInvalidParameterCount
+// compileMin compiles the min validator that tests whether a variable value is
+// larger or equal to a given number. For number types, it's a simple lesser-than
+// test; for strings it tests the number of characters whereas for maps and
+// slices it tests the number of items.
+func compileMin(params []string) (ValidatorFunc, error) {
+	p, err := parseNumberParams(params, 1)
+	if err != nil {
+		return nil, err
 	}
 
-	st := reflect.ValueOf(v)
-	invalid := false
-	switch st.Kind() {
-	case reflect.String:
-		p, err := asInt(params[0])
+	return func(v interface{}, _ []string) error {
+		c, err := compareNumber(v, p[0])
 		if err != nil {
-			return ErrBadParameter
+			return err
 		}
-		invalid = int64(len(st.String())) < p
-	case reflect.Slice, reflect.Map, reflect.Array:
-		p, err := asInt(params[0])
-		if err != nil {
-			return ErrBadParameter
+		if c < 0 {
+			return ErrMin
 		}
-		invalid = int64(st.Len()) < p
-	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		p, err := asInt(params[0])
-		if err != nil {
-			return ErrBadParameter
-		}
-		invalid = st.Int() < p
-	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
-		p, err := asUint(params[0])
-		if err != nil {
-			return ErrBadParameter
-		}
-		invalid = st.Uint() < p
-	case reflect.Float32, reflect.Float64:
-		p, err := asFloat(params[0])
-		if err != nil {
-			return ErrBadParameter
-		}
-		invalid = st.Float() < p
-	default:
-		return ErrUnsupported
-	}
-	if invalid {
-		return ErrMin
-	}
-	return nil
+		return nil
+	}, nil
 }
 
-// max tests whether a variable value is lesser than a given
-// value. For numbers, it's a simple lesser-than test; for
-// strings it tests the number of characters whereas for maps
-// and slices it tests the number of items.
-func max(v interface{}, params []string) error {
-
-	if len(params) != 1 {
-		return ErrInvalidParameterCount
+// compileMax compiles the max validator that tests whether a variable value is
+// lesser than a given value. For numbers, it's a simple lesser-than test; for
+// strings it tests the number of characters whereas for maps and slices it
+// tests the number of items.
+func compileMax(params []string) (ValidatorFunc, error) {
+	p, err := parseNumberParams(params, 1)
+	if err != nil {
+		return nil, err
 	}
 
-	st := reflect.ValueOf(v)
-	var invalid bool
-	switch st.Kind() {
-	case reflect.String:
-		p, err := asInt(params[0])
+	return func(v interface{}, _ []string) error {
+		c, err := compareNumber(v, p[0])
 		if err != nil {
-			return ErrBadParameter
+			return err
 		}
-		invalid = int64(len(st.String())) > p
-	case reflect.Slice, reflect.Map, reflect.Array:
-		p, err := asInt(params[0])
-		if err != nil {
-			return ErrBadParameter
+		if c > 0 {
+			return ErrMax
 		}
-		invalid = int64(st.Len()) > p
-	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		p, err := asInt(params[0])
-		if err != nil {
-			return ErrBadParameter
-		}
-		invalid = st.Int() > p
-	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
-		p, err := asUint(params[0])
-		if err != nil {
-			return ErrBadParameter
-		}
-		invalid = st.Uint() > p
-	case reflect.Float32, reflect.Float64:
-		p, err := asFloat(params[0])
-		if err != nil {
-			return ErrBadParameter
-		}
-		invalid = st.Float() > p
-	default:
-		return ErrUnsupported
-	}
-	if invalid {
-		return ErrMax
-	}
-	return nil
+		return nil
+	}, nil
 }
 
-// regex is the builtin validation function that checks
-// whether the string variable matches a regular expression
-func regex(v interface{}, params []string) error {
-	s, ok := v.(string)
-	if !ok {
-		return ErrUnsupported
-	}
-
+// compileRegexp compiles the regexp validator that checks whether the string
+// variable matches a regular expression
+func compileRegexp(params []string) (ValidatorFunc, error) {
 	if len(params) != 1 {
-		return ErrInvalidParameterCount
+		return nil, ErrInvalidParameterCount
 	}
 
 	re, err := regexp.Compile(params[0])
 	if err != nil {
-		return ErrBadParameter
+		return nil, ErrBadParameter
 	}
 
-	if !re.MatchString(s) {
-		return ErrRegexp
-	}
-	return nil
+	return func(v interface{}, _ []string) error {
+		s, ok := v.(string)
+		if !ok {
+			return ErrUnsupported
+		}
+
+		if !re.MatchString(s) {
+			return ErrRegexp
+		}
+		return nil
+	}, nil
 }
 
-func between(v interface{}, params []string) error {
-	if len(params) != 2 {
-		return ErrInvalidParameterCount
+// compileBetween compiles the between validator that tests whether a variable
+// value lies within the two given values, in either order
+func compileBetween(params []string) (ValidatorFunc, error) {
+	p, err := parseNumberParams(params, 2)
+	if err != nil {
+		return nil, err
 	}
 
-	st := reflect.ValueOf(v)
-	var invalid bool
-	switch st.Kind() {
-	case reflect.String:
-		a, err := asInt(params[0])
+	return func(v interface{}, _ []string) error {
+		a, b, err := compareNumbers(v, p[0], p[1])
 		if err != nil {
-			return ErrBadParameter
+			return err
 		}
 
-		b, err := asInt(params[1])
-		if err != nil {
-			return ErrBadParameter
+		// below or above both values
+		if a*b > 0 {
+			return ErrBetween
 		}
+		return nil
+	}, nil
+}
 
-		len := int64(len(st.String()))
-		if a > b {
-			invalid = len < b || len > a
-		} else { //inverse
-			invalid = len < a || len > b
-		}
-	case reflect.Slice, reflect.Map, reflect.Array:
-		a, err := asInt(params[0])
-		if err != nil {
-			return ErrBadParameter
-		}
+// compileAround compiles the around validator that tests whether a variable
+// value lies outside the two given values, in either order
+func compileAround(params []string) (ValidatorFunc, error) {
+	p, err := parseNumberParams(params, 2)
+	if err != nil {
+		return nil, err
+	}
 
-		b, err := asInt(params[1])
+	return func(v interface{}, _ []string) error {
+		a, b, err := compareNumbers(v, p[0], p[1])
 		if err != nil {
-			return ErrBadParameter
+			return err
 		}
 
-		len := int64(st.Len())
-		if a > b {
-			invalid = len < b || len > a
-		} else { //inverse
-			invalid = len < a || len > b
+		// strictly between both values
+		if a*b < 0 {
+			return ErrAround
 		}
+		return nil
+	}, nil
+}
 
-	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		a, err := asInt(params[0])
-		if err != nil {
-			return ErrBadParameter
-		}
+// numberParam holds a parameter parsed for every kind of value it can be
+// compared with, the error is kept for the kinds the parameter is not valid for
+type numberParam struct {
+	i    int64
+	iErr error
+	u    uint64
+	uErr error
+	f    float64
+	fErr error
+}
 
-		b, err := asInt(params[1])
-		if err != nil {
-			return ErrBadParameter
-		}
+// parseNumberParams parses the expected number of parameters, a parameter that
+// is not a number for any kind of value is a bad parameter
+func parseNumberParams(params []string, count int) ([]numberParam, error) {
+	if len(params) != count {
+		return nil, ErrInvalidParameterCount
+	}
 
-		val := st.Int()
-		if a > b {
-			invalid = val < b || val > a
-		} else { //inverse
-			invalid = val < a || val > b
-		}
-	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
-		a, err := asUint(params[0])
-		if err != nil {
-			return ErrBadParameter
+	p := make([]numberParam, len(params))
+	for i, param := range params {
+		p[i].i, p[i].iErr = asInt(param)
+		p[i].u, p[i].uErr = asUint(param)
+		p[i].f, p[i].fErr = asFloat(param)
+		if p[i].iErr != nil && p[i].uErr != nil && p[i].fErr != nil {
+			return nil, ErrBadParameter
 		}
+	}
+	return p, nil
+}
 
-		b, err := asUint(params[1])
-		if err != nil {
-			return ErrBadParameter
-		}
+// paramCheckFunc checks the params of a validator against the type of the values
+// it validates when the rules of a structure are compiled
+type paramCheckFunc func(params []string, t reflect.Type) error
 
-		val := st.Uint()
-		if a > b {
-			invalid = val < b || val > a
-		} else { //inverse
-			invalid = val < a || val > b
-		}
-	case reflect.Float32, reflect.Float64:
-		a, err := asFloat(params[0])
-		if err != nil {
-			return ErrBadParameter
+// checkNumberParams checks the number params can be compared with values of the
+// type, the length is compared for strings, slices, arrays and maps
+func checkNumberParams(params []string, t reflect.Type) error {
+	for _, param := range params {
+		var err error
+		switch t.Kind() {
+		case reflect.String, reflect.Slice, reflect.Map, reflect.Array,
+			reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			_, err = asInt(param)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			_, err = asUint(param)
+		case reflect.Float32, reflect.Float64:
+			_, err = asFloat(param)
 		}
 
-		b, err := asFloat(params[1])
 		if err != nil {
-			return ErrBadParameter
+			return err
 		}
-
-		val := st.Float()
-		if a > b {
-			invalid = val < b || val > a
-		} else { //inverse
-			invalid = val < a || val > b
-		}
-
-	default:
-		return ErrUnsupported
-	}
-	if invalid {
-		return ErrBetween
 	}
 	return nil
 }
 
-func around(v interface{}, params []string) error {
-	if len(params) != 2 {
-		return ErrInvalidParameterCount
+// compareNumbers compares the value with both parameters
+func compareNumbers(v interface{}, a, b numberParam) (int, int, error) {
+	ca, err := compareNumber(v, a)
+	if err != nil {
+		return 0, 0, err
 	}
 
+	cb, err := compareNumber(v, b)
+	if err != nil {
+		return 0, 0, err
+	}
+	return ca, cb, nil
+}
+
+// compareNumber compares the number, or the length for strings, slices, arrays
+// and maps, of the value with the parameter. It returns -1 when the value is
+// less than the parameter, 0 when equal and +1 when greater.
+func compareNumber(v interface{}, p numberParam) (int, error) {
 	st := reflect.ValueOf(v)
-	var invalid bool
 	switch st.Kind() {
 	case reflect.String:
-		a, err := asInt(params[0])
-		if err != nil {
-			return ErrBadParameter
-		}
-
-		b, err := asInt(params[1])
-		if err != nil {
-			return ErrBadParameter
-		}
-
-		len := int64(len(st.String()))
-		if a < b {
-			invalid = len < b && len > a
-		} else { //inverse
-			invalid = len < a && len > b
+		if p.iErr != nil {
+			return 0, ErrBadParameter
 		}
+		return compareInt(int64(len(st.String())), p.i), nil
 	case reflect.Slice, reflect.Map, reflect.Array:
-		a, err := asInt(params[0])
-		if err != nil {
-			return ErrBadParameter
+		if p.iErr != nil {
+			return 0, ErrBadParameter
 		}
-
-		b, err := asInt(params[1])
-		if err != nil {
-			return ErrBadParameter
-		}
-
-		len := int64(st.Len())
-		if a < b {
-			invalid = len < b && len > a
-		} else { //inverse
-			invalid = len < a && len > b
-		}
-
+		return compareInt(int64(st.Len()), p.i), nil
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		a, err := asInt(params[0])
-		if err != nil {
-			return ErrBadParameter
-		}
-
-		b, err := asInt(params[1])
-		if err != nil {
-			return ErrBadParameter
-		}
-
-		val := st.Int()
-		if a < b {
-			invalid = val < b && val > a
-		} else { //inverse
-			invalid = val < a && val > b
+		if p.iErr != nil {
+			return 0, ErrBadParameter
 		}
+		return compareInt(st.Int(), p.i), nil
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
-		a, err := asUint(params[0])
-		if err != nil {
-			return ErrBadParameter
+		if p.uErr != nil {
+			return 0, ErrBadParameter
 		}
-
-		b, err := asUint(params[1])
-		if err != nil {
-			return ErrBadParameter
-		}
-
-		val := st.Uint()
-		if a < b {
-			invalid = val < b && val > a
-		} else { //inverse
-			invalid = val < a && val > b
+		switch val := st.Uint(); {
+		case val < p.u:
+			return -1, nil
+		case val > p.u:
+			return 1, nil
 		}
+		return 0, nil
 	case reflect.Float32, reflect.Float64:
-		a, err := asFloat(params[0])
-		if err != nil {
-			return ErrBadParameter
-		}
-
-		b, err := asFloat(params[1])
-		if err != nil {
-			return ErrBadParameter
+		if p.fErr != nil {
+			return 0, ErrBadParameter
 		}
-
-		val := st.Float()
-		if a < b {
-			invalid = val < b && val > a
-		} else { //inverse
-			invalid = val < a && val > b
+		switch val := st.Float(); {
+		case val < p.f:
+			return -1, nil
+		case val > p.f:
+			return 1, nil
 		}
-
-	default:
-		return ErrUnsupported
+		return 0, nil
 	}
-	if invalid {
-		return ErrAround
+	return 0, ErrUnsupported
+}
+
+// compareInt returns -1, 0 or +1 when a is less than, equal to or greater than b
+func compareInt(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
 	}
-	return nil
+	return 0
 }
 
 func include(i interface{}, params []string) error {
@@ -727,6 +606,316 @@ func enum(v interface{}, params []string) error {
 	return nil
 }
 
+// eqField tests whether the value is equal to the value of the field
+// referred to by the parameter
+func eqField(fc *FieldContext) error {
+	cmp, err := compareField(fc.Value, fc.Parent, fc.Params)
+	if err == errNilField {
+		return ErrEqField
+	} else if err != nil {
+		return err
+	}
+
+	if cmp != 0 {
+		return ErrEqField
+	}
+	return nil
+}
+
+// neField tests whether the value is not equal to the value of the field
+// referred to by the parameter
+func neField(fc *FieldContext) error {
+	cmp, err := compareField(fc.Value, fc.Parent, fc.Params)
+	if err == errNilField {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	if cmp == 0 {
+		return ErrNeField
+	}
+	return nil
+}
+
+// gtField tests whether the value is greater than the value of the field
+// referred to by the parameter
+func gtField(fc *FieldContext) error {
+	cmp, err := compareField(fc.Value, fc.Parent, fc.Params)
+	if err == errNilField {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	if cmp <= 0 {
+		return ErrGtField
+	}
+	return nil
+}
+
+// ltField tests whether the value is less than the value of the field
+// referred to by the parameter
+func ltField(fc *FieldContext) error {
+	cmp, err := compareField(fc.Value, fc.Parent, fc.Params)
+	if err == errNilField {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	if cmp >= 0 {
+		return ErrLtField
+	}
+	return nil
+}
+
+// requiredIf tests whether the value is non-zero when the field referred to by
+// the first parameter matches one of the other parameters. The remaining
+// validators are skipped when the condition is not met.
+func requiredIf(fc *FieldContext) error {
+	match, err := matchField(fc.Parent, fc.Params)
+	if err != nil {
+		return err
+	}
+
+	if !match {
+		return errOmitEmpty
+	}
+	return required(fc.Value, nil)
+}
+
+// requiredWith tests whether the value is non-zero when any of the fields
+// referred to by the parameters is non-zero. The remaining validators are
+// skipped when the condition is not met.
+func requiredWith(fc *FieldContext) error {
+	present, err := presentFields(fc.Parent, fc.Params)
+	if err != nil {
+		return err
+	}
+
+	if present == 0 {
+		return errOmitEmpty
+	}
+	return required(fc.Value, nil)
+}
+
+// requiredWithout tests whether the value is non-zero when any of the fields
+// referred to by the parameters is zero. The remaining validators are skipped
+// when the condition is not met.
+func requiredWithout(fc *FieldContext) error {
+	present, err := presentFields(fc.Parent, fc.Params)
+	if err != nil {
+		return err
+	}
+
+	if present == len(fc.Params) {
+		return errOmitEmpty
+	}
+	return required(fc.Value, nil)
+}
+
+// excludedIf tests whether the value is zero when the field referred to by
+// the first parameter matches one of the other parameters
+func excludedIf(fc *FieldContext) error {
+	match, err := matchField(fc.Parent, fc.Params)
+	if err != nil {
+		return err
+	}
+
+	if !match {
+		return nil
+	}
+
+	if required(fc.Value, nil) == ErrRequired {
+		return errOmitEmpty
+	}
+	return ErrExcluded
+}
+
+// matchField reports whether the value of the field found by the dotted path
+// in the first parameter matches one of the other parameters
+func matchField(parent reflect.Value, params []string) (bool, error) {
+	if len(params) < 2 {
+		return false, ErrInvalidParameterCount
+	}
+
+	if parent.Kind() != reflect.Struct {
+		return false, ErrUnsupported
+	}
+
+	field, err := resolveField(parent, params[0])
+	if err != nil {
+		return false, err
+	}
+
+	field = reflect.Indirect(field)
+	if !field.IsValid() {
+		return false, nil
+	}
+
+	str := fmt.Sprintf("%v", field.Interface())
+	for _, param := range params[1:] {
+		if str == param {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// presentFields returns the number of non-zero fields found by the
+// dotted paths in the parameters
+func presentFields(parent reflect.Value, params []string) (int, error) {
+	if len(params) < 1 {
+		return 0, ErrInvalidParameterCount
+	}
+
+	if parent.Kind() != reflect.Struct {
+		return 0, ErrUnsupported
+	}
+
+	present := 0
+	for _, param := range params {
+		field, err := resolveField(parent, param)
+		if err != nil {
+			return 0, err
+		}
+
+		if required(field.Interface(), nil) != ErrRequired {
+			present++
+		}
+	}
+	return present, nil
+}
+
+// compareField compares the value with the value of the field found by the
+// dotted path in the first parameter. The path is resolved against the parent
+// structure holding the value.
+func compareField(v interface{}, parent reflect.Value, params []string) (int, error) {
+	if len(params) != 1 {
+		return 0, ErrInvalidParameterCount
+	}
+
+	if parent.Kind() != reflect.Struct {
+		return 0, ErrUnsupported
+	}
+
+	field, err := resolveField(parent, params[0])
+	if err != nil {
+		return 0, err
+	}
+
+	a, b := reflect.ValueOf(v), field
+	for a.Kind() == reflect.Ptr && !a.IsNil() {
+		a = a.Elem()
+	}
+	for b.Kind() == reflect.Ptr && !b.IsNil() {
+		b = b.Elem()
+	}
+
+	aNil := a.Kind() == reflect.Invalid || a.Kind() == reflect.Ptr
+	bNil := b.Kind() == reflect.Invalid || b.Kind() == reflect.Ptr
+	if aNil && bNil {
+		return 0, nil
+	} else if aNil || bNil {
+		return 0, errNilField
+	}
+	return compareValues(a, b)
+}
+
+// resolveField returns the field found by following the dotted path of
+// field names from the structure, unexported fields are not found
+func resolveField(value reflect.Value, path string) (reflect.Value, error) {
+	for _, name := range strings.Split(path, ".") {
+		for value.Kind() == reflect.Ptr {
+			if value.IsNil() {
+				return value, nil
+			}
+			value = value.Elem()
+		}
+
+		if value.Kind() != reflect.Struct {
+			return reflect.Value{}, ErrBadParameter
+		}
+
+		sf, ok := value.Type().FieldByName(name)
+		if !ok || sf.PkgPath != "" {
+			return reflect.Value{}, ErrBadParameter
+		}
+		value = value.FieldByIndex(sf.Index)
+	}
+	return value, nil
+}
+
+// compareValues compares two numbers, strings or times and returns -1, 0 or 1
+// when a is less than, equal to or greater than b. Values of unexported fields
+// can not be compared.
+func compareValues(a, b reflect.Value) (int, error) {
+	if !a.CanInterface() || !b.CanInterface() {
+		return 0, ErrBadParameter
+	}
+
+	if a.Type() == timeType {
+		if b.Type() != timeType {
+			return 0, ErrUnsupported
+		}
+		ta, tb := a.Interface().(time.Time), b.Interface().(time.Time)
+
+		switch {
+		case ta.Before(tb):
+			return -1, nil
+		case ta.After(tb):
+			return 1, nil
+		}
+		return 0, nil
+	}
+
+	var less, greater bool
+	switch {
+	case a.Kind() == reflect.String && b.Kind() == reflect.String:
+		return strings.Compare(a.String(), b.String()), nil
+	case isInt(a.Kind()) && isInt(b.Kind()):
+		less, greater = a.Int() < b.Int(), a.Int() > b.Int()
+	case isUint(a.Kind()) && isUint(b.Kind()):
+		less, greater = a.Uint() < b.Uint(), a.Uint() > b.Uint()
+	case isNumber(a.Kind()) && isNumber(b.Kind()):
+		less, greater = asNumber(a) < asNumber(b), asNumber(a) > asNumber(b)
+	default:
+		return 0, ErrUnsupported
+	}
+
+	switch {
+	case less:
+		return -1, nil
+	case greater:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+func isInt(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Int64
+}
+
+func isUint(k reflect.Kind) bool {
+	return k >= reflect.Uint && k <= reflect.Uintptr
+}
+
+func isNumber(k reflect.Kind) bool {
+	return isInt(k) || isUint(k) || k == reflect.Float32 || k == reflect.Float64
+}
+
+// asNumber returns a numeric value as a float64
+func asNumber(v reflect.Value) float64 {
+	switch {
+	case isInt(v.Kind()):
+		return float64(v.Int())
+	case isUint(v.Kind()):
+		return float64(v.Uint())
+	}
+	return v.Float()
+}
+
 // asInt returns the parameter as a int64
 // or panics if it can't convert
 func asInt(param string) (int64, error) {
@@ -0,0 +1,95 @@
+package validate
+
+import (
+	"reflect"
+	"sync"
+)
+
+// ruleCache holds the compiled structure rules for a configuration. The rules
+// are stored once and read many times, lookups of cached rules do not lock.
+type ruleCache struct {
+	cfg      *config               // configuration the rules are compiled with
+	rules    sync.Map              // compiled *rules indexed by ruleKey
+	mu       sync.Mutex            // guards inflight
+	inflight map[ruleKey]*inflight // structures being compiled
+}
+
+// inflight is a structure compilation that callers for the same structure wait on
+type inflight struct {
+	done  chan struct{} // closed when the compilation is finished
+	rules *rules
+	err   error
+}
+
+// newRuleCache returns an empty cache for the configuration
+func newRuleCache(cfg *config) *ruleCache {
+	return &ruleCache{
+		cfg:      cfg,
+		inflight: make(map[ruleKey]*inflight),
+	}
+}
+
+// get returns the rules for the structure and groups from the cache
+func (c *ruleCache) get(t reflect.Type, groups groupSet) (*rules, bool) {
+	cached, ok := c.rules.Load(ruleKey{typ: t, groups: groups.key})
+	if !ok {
+		return nil, false
+	}
+	return cached.(*rules), true
+}
+
+// getOrCompile returns the rules from the cache or compiles them. Concurrent
+// calls for the same structure and groups wait for a single compilation.
+func (c *ruleCache) getOrCompile(t reflect.Type, groups groupSet, compile func() (*rules, map[reflect.Type]*rules, error)) (*rules, error) {
+	if rules, ok := c.get(t, groups); ok {
+		return rules, nil
+	}
+
+	key := ruleKey{typ: t, groups: groups.key}
+	c.mu.Lock()
+	// the rules can have been stored while waiting for the lock
+	if rules, ok := c.get(t, groups); ok {
+		c.mu.Unlock()
+		return rules, nil
+	}
+
+	if call, ok := c.inflight[key]; ok {
+		c.mu.Unlock()
+		<-call.done
+		if call.rules == nil && call.err == nil {
+			// the compilation panicked, compile the structure again
+			return c.getOrCompile(t, groups, compile)
+		}
+		return call.rules, call.err
+	}
+
+	call := &inflight{done: make(chan struct{})}
+	c.inflight[key] = call
+	c.mu.Unlock()
+
+	// the waiters are released even when a compiler func panics
+	defer func() {
+		c.mu.Lock()
+		delete(c.inflight, key)
+		c.mu.Unlock()
+		close(call.done)
+	}()
+
+	rules, pending, err := compile()
+	if err != nil {
+		call.err = err
+		return nil, err
+	}
+
+	c.store(groups, pending)
+	call.rules = rules
+	return rules, nil
+}
+
+// store adds the compiled structures to the cache, rules already cached by
+// another compilation are kept
+func (c *ruleCache) store(groups groupSet, compiled map[reflect.Type]*rules) {
+	for t, rules := range compiled {
+		c.rules.LoadOrStore(ruleKey{typ: t, groups: groups.key}, rules)
+	}
+}
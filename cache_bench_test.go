@@ -0,0 +1,108 @@
+package validate_test
+
+import (
+	"github.com/mbict/go-validate"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// structValidator validates structures, the validator or the baseline of the benchmarks
+type structValidator interface {
+	Validate(v interface{}) error
+}
+
+// distinctValues returns values of n distinct structure types, each with a
+// nested structure, to fill the rules cache with many entries
+func distinctValues(n int) []interface{} {
+	values := make([]interface{}, n)
+	for i := range values {
+		nested := reflect.StructOf([]reflect.StructField{
+			{Name: "Street", Type: reflect.TypeOf(""), Tag: reflect.StructTag(`validate:"required;max(` + strconv.Itoa(i+10) + `)"`)},
+		})
+		t := reflect.StructOf([]reflect.StructField{
+			{Name: "Name", Type: reflect.TypeOf(""), Tag: `validate:"required;min(3)"`},
+			{Name: "Age", Type: reflect.TypeOf(0), Tag: reflect.StructTag(`validate:"between(` + strconv.Itoa(i) + `,200)"`)},
+			{Name: "Address", Type: nested},
+		})
+
+		v := reflect.New(t).Elem()
+		v.Field(0).SetString("name")
+		v.Field(1).SetInt(int64(i))
+		v.Field(2).Field(0).SetString("street")
+		values[i] = v.Interface()
+	}
+	return values
+}
+
+// BenchmarkValidateParallel validates many distinct types in parallel with a warm
+// cache. The rwmutex baseline uses the map guarded by a sync.RWMutex the cache
+// replaced.
+func BenchmarkValidateParallel(b *testing.B) {
+	b.Run("cache", func(b *testing.B) {
+		benchmarkValidateParallel(b, validate.NewValidator())
+	})
+	b.Run("rwmutex", func(b *testing.B) {
+		benchmarkValidateParallel(b, validate.NewLockedValidator())
+	})
+}
+
+func benchmarkValidateParallel(b *testing.B, v structValidator) {
+	values := distinctValues(256)
+	for _, value := range values {
+		if err := v.Validate(value); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			if err := v.Validate(values[i%len(values)]); err != nil {
+				b.Error(err)
+				return
+			}
+			i++
+		}
+	})
+}
+
+// BenchmarkValidateParallelCold validates many distinct types in parallel with an
+// empty cache, every goroutine validates all the types in a different order. The
+// rwmutex baseline compiles the rules under the write lock of the map the cache
+// replaced.
+func BenchmarkValidateParallelCold(b *testing.B) {
+	b.Run("cache", func(b *testing.B) {
+		benchmarkValidateParallelCold(b, func() structValidator { return validate.NewValidator() })
+	})
+	b.Run("rwmutex", func(b *testing.B) {
+		benchmarkValidateParallelCold(b, func() structValidator { return validate.NewLockedValidator() })
+	})
+}
+
+func benchmarkValidateParallelCold(b *testing.B, newValidator func() structValidator) {
+	const goroutines = 16
+	values := distinctValues(256)
+
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		v := newValidator()
+
+		var wg sync.WaitGroup
+		wg.Add(goroutines)
+		for g := 0; g < goroutines; g++ {
+			go func(g int) {
+				defer wg.Done()
+				for i := range values {
+					if err := v.Validate(values[(i+g*len(values)/goroutines)%len(values)]); err != nil {
+						b.Error(err)
+						return
+					}
+				}
+			}(g)
+		}
+		wg.Wait()
+	}
+}
@@ -0,0 +1,87 @@
+package validate_test
+
+import (
+	"github.com/mbict/go-validate"
+	. "gopkg.in/check.v1"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type CacheSuite struct{}
+
+var _ = Suite(&CacheSuite{})
+
+// countingCompiler returns a compiler that counts the compilations and takes
+// some time to let concurrent callers pile up
+func countingCompiler(count *int32, err error) validate.CompilerFunc {
+	return func(params []string) (validate.ValidatorFunc, error) {
+		atomic.AddInt32(count, 1)
+		time.Sleep(10 * time.Millisecond)
+		if err != nil {
+			return nil, err
+		}
+		return failing, nil
+	}
+}
+
+func (s *CacheSuite) TestConcurrentCompilationIsDeduplicated(c *C) {
+	type nested struct {
+		B string `validate:"counted"`
+	}
+	type test struct {
+		A string `validate:"counted"`
+		N nested
+	}
+
+	var count int32
+	v := validate.NewValidator()
+	c.Assert(v.SetValidationCompiler("counted", countingCompiler(&count, nil)), IsNil)
+
+	var wg sync.WaitGroup
+	errs := make([]error, 16)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = v.Validate(test{})
+		}(i)
+	}
+	wg.Wait()
+
+	c.Assert(atomic.LoadInt32(&count), Equals, int32(2))
+	for _, err := range errs {
+		c.Assert(err, NotNil)
+	}
+
+	// the nested structure is cached as well
+	c.Assert(v.Validate(nested{}), NotNil)
+	c.Assert(atomic.LoadInt32(&count), Equals, int32(2))
+}
+
+func (s *CacheSuite) TestFailedCompilationIsNotCached(c *C) {
+	type test struct {
+		A string `validate:"counted"`
+	}
+
+	var count int32
+	v := validate.NewValidator()
+	c.Assert(v.SetValidationCompiler("counted", countingCompiler(&count, validate.ErrBadParameter)), IsNil)
+
+	c.Assert(v.Validate(test{}), Equals, validate.ErrBadParameter)
+	c.Assert(v.Validate(test{}), Equals, validate.ErrBadParameter)
+	c.Assert(atomic.LoadInt32(&count), Equals, int32(2))
+}
+
+func (s *CacheSuite) TestConfigurationResetsCache(c *C) {
+	type test struct {
+		A string `validate:"custom"`
+	}
+
+	v := validate.NewValidator()
+	c.Assert(v.SetValidationFunc("custom", failing), IsNil)
+	c.Assert(v.Validate(test{}), NotNil)
+
+	c.Assert(v.SetValidationFunc("custom", func(v interface{}, params []string) error { return nil }), IsNil)
+	c.Assert(v.Validate(test{}), IsNil)
+}
@@ -0,0 +1,83 @@
+package validate
+
+// EnglishCatalog holds the English messages for the builtin validators
+var EnglishCatalog = Catalog{
+	valueKey:                  "value",
+	"required":                "{field} is required",
+	"not_empty":               "{field} must not be empty",
+	"len":                     "{field} must have a size of {0}",
+	"min":                     "{field} must be at least {0}",
+	"max":                     "{field} must be at most {0}",
+	"between":                 "{field} must be between {0} and {1}",
+	"around":                  "{field} must not be between {0} and {1}",
+	"in":                      "{field} must be one of {params}",
+	"exclude":                 "{field} must not be one of {params}",
+	"regexp":                  "{field} does not match the required format",
+	"url":                     "{field} must be a valid URL",
+	"email":                   "{field} must be a valid email address",
+	"numeric":                 "{field} must be numeric",
+	"number":                  "{field} must be a number",
+	"identifier":              "{field} must be a valid identifier",
+	"alpha_dash":              "{field} may only contain letters, digits, dashes and underscores",
+	"alpha_dash_dot":          "{field} may only contain letters, digits, dashes, underscores and dots",
+	"alpha":                   "{field} may only contain letters",
+	"alphanumeric":            "{field} may only contain letters and digits",
+	"uuid":                    "{field} must be a valid UUID",
+	"uuid3":                   "{field} must be a valid version 3 UUID",
+	"uuid4":                   "{field} must be a valid version 4 UUID",
+	"uuid5":                   "{field} must be a valid version 5 UUID",
+	"base64":                  "{field} must be base64 encoded",
+	"enum":                    "{field} must be one of {params}",
+	"eqfield":                 "{field} must be equal to {0}",
+	"nefield":                 "{field} must not be equal to {0}",
+	"gtfield":                 "{field} must be greater than {0}",
+	"ltfield":                 "{field} must be less than {0}",
+	"required_if":             "{field} is required when {0} is {1}",
+	"required_with":           "{field} is required when {params} is present",
+	"required_without":        "{field} is required when {params} is not present",
+	"excluded_if":             "{field} must be empty when {0} is {1}",
+	"unsupported":             "{field} has an unsupported type",
+	"bad_parameter":           "{field} is validated with a bad parameter",
+	"invalid_parameter_count": "{field} is validated with an invalid number of parameters",
+}
+
+// DutchCatalog holds the Dutch messages for the builtin validators
+var DutchCatalog = Catalog{
+	valueKey:                  "waarde",
+	"required":                "{field} is verplicht",
+	"not_empty":               "{field} mag niet leeg zijn",
+	"len":                     "{field} moet een grootte van {0} hebben",
+	"min":                     "{field} moet minimaal {0} zijn",
+	"max":                     "{field} mag maximaal {0} zijn",
+	"between":                 "{field} moet tussen {0} en {1} liggen",
+	"around":                  "{field} mag niet tussen {0} en {1} liggen",
+	"in":                      "{field} moet een van {params} zijn",
+	"exclude":                 "{field} mag niet een van {params} zijn",
+	"regexp":                  "{field} heeft niet het vereiste formaat",
+	"url":                     "{field} moet een geldige URL zijn",
+	"email":                   "{field} moet een geldig e-mailadres zijn",
+	"numeric":                 "{field} moet numeriek zijn",
+	"number":                  "{field} moet een getal zijn",
+	"identifier":              "{field} moet een geldige identifier zijn",
+	"alpha_dash":              "{field} mag alleen letters, cijfers, streepjes en underscores bevatten",
+	"alpha_dash_dot":          "{field} mag alleen letters, cijfers, streepjes, underscores en punten bevatten",
+	"alpha":                   "{field} mag alleen letters bevatten",
+	"alphanumeric":            "{field} mag alleen letters en cijfers bevatten",
+	"uuid":                    "{field} moet een geldige UUID zijn",
+	"uuid3":                   "{field} moet een geldige versie 3 UUID zijn",
+	"uuid4":                   "{field} moet een geldige versie 4 UUID zijn",
+	"uuid5":                   "{field} moet een geldige versie 5 UUID zijn",
+	"base64":                  "{field} moet base64 gecodeerd zijn",
+	"enum":                    "{field} moet een van {params} zijn",
+	"eqfield":                 "{field} moet gelijk zijn aan {0}",
+	"nefield":                 "{field} mag niet gelijk zijn aan {0}",
+	"gtfield":                 "{field} moet groter zijn dan {0}",
+	"ltfield":                 "{field} moet kleiner zijn dan {0}",
+	"required_if":             "{field} is verplicht wanneer {0} gelijk is aan {1}",
+	"required_with":           "{field} is verplicht wanneer {params} aanwezig is",
+	"required_without":        "{field} is verplicht wanneer {params} niet aanwezig is",
+	"excluded_if":             "{field} moet leeg zijn wanneer {0} gelijk is aan {1}",
+	"unsupported":             "{field} heeft een niet ondersteund type",
+	"bad_parameter":           "{field} wordt gevalideerd met een ongeldige parameter",
+	"invalid_parameter_count": "{field} wordt gevalideerd met een ongeldig aantal parameters",
+}
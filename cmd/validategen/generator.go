@@ -0,0 +1,629 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/mbict/go-tags"
+	"go/ast"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"math"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// markers that need the runtime to validate the field
+var runtimeMarkers = map[string]bool{
+	"dive":     true,
+	"keys":     true,
+	"endkeys":  true,
+	"group":    true,
+	"endgroup": true,
+}
+
+// context validators that need the parent structure of the field
+var parentValidators = map[string]bool{
+	"eqfield":          true,
+	"nefield":          true,
+	"gtfield":          true,
+	"ltfield":          true,
+	"required_if":      true,
+	"required_with":    true,
+	"required_without": true,
+	"excluded_if":      true,
+}
+
+// sentinel errors returned by the generated validators
+var sentinels = map[string]string{
+	"required": "ErrRequired",
+	"len":      "ErrLen",
+	"min":      "ErrMin",
+	"max":      "ErrMax",
+	"between":  "ErrBetween",
+	"regexp":   "ErrRegexp",
+	"in":       "ErrInclude",
+	"exclude":  "ErrExclude",
+}
+
+// nameResolver resolves the name of a field as used in the errors
+type nameResolver func(name string, tag reflect.StructTag) string
+
+var nameResolvers = map[string]nameResolver{
+	"go": func(name string, _ reflect.StructTag) string {
+		return name
+	},
+	"json": jsonName,
+	"json_snake": func(name string, tag reflect.StructTag) string {
+		return toSnakeCase(jsonName(name, tag))
+	},
+}
+
+// runtimeResolvers holds the expressions of the validate name resolvers matching
+// the name resolvers, they name the errors of the fields validated at runtime
+var runtimeResolvers = map[string]string{
+	"go":         "validate.DefaultNameResolver",
+	"json":       "validate.JsonNameResolver",
+	"json_snake": "validate.JsonNameSnakeCaseResolver",
+}
+
+// generator generates the Validate methods of a package
+type generator struct {
+	tagName         string       // structure tag holding the validators
+	resolver        nameResolver // resolves the names used in the errors
+	runtimeResolver string       // expression of the matching validate name resolver
+
+	vars       bytes.Buffer          // package level variables
+	body       bytes.Buffer          // generated methods
+	usesRegexp bool                  // generated code uses the regexp package
+	generated  map[*types.Named]bool // types a Validate method is generated for
+}
+
+// generate generates the Validate methods for the named types of the package
+// found in the directory, or all structures with tags when no names are given
+func (g *generator) generate(dir, outputFile string, typeNames []string) ([]byte, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go") && fi.Name() != outputFile
+	}, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("expected a single package in %s, found %d", dir, len(pkgs))
+	}
+
+	var pkgAst *ast.Package
+	for _, p := range pkgs {
+		pkgAst = p
+	}
+
+	fileNames := make([]string, 0, len(pkgAst.Files))
+	for name := range pkgAst.Files {
+		fileNames = append(fileNames, name)
+	}
+	sort.Strings(fileNames)
+
+	files := make([]*ast.File, 0, len(fileNames))
+	for _, name := range fileNames {
+		files = append(files, pkgAst.Files[name])
+	}
+
+	// errors are ignored, the package may use methods that are not generated yet
+	conf := types.Config{
+		Importer: importer.ForCompiler(fset, "source", nil),
+		Error:    func(error) {},
+	}
+	pkg, _ := conf.Check(pkgAst.Name, fset, files, nil)
+
+	named, err := g.selectTypes(pkg, typeNames)
+	if err != nil {
+		return nil, err
+	}
+
+	g.generated = make(map[*types.Named]bool, len(named))
+	for _, t := range named {
+		g.generated[t] = true
+	}
+
+	for _, t := range named {
+		if err := g.writeMethod(t); err != nil {
+			return nil, err
+		}
+	}
+
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "// Code generated by validategen; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&src, "package %s\n\n", pkg.Name())
+	fmt.Fprintf(&src, "import (\n")
+	fmt.Fprintf(&src, "\t\"github.com/mbict/go-validate\"\n")
+	if g.usesRegexp {
+		fmt.Fprintf(&src, "\t\"regexp\"\n")
+	}
+	fmt.Fprintf(&src, ")\n\n")
+	if g.vars.Len() > 0 {
+		fmt.Fprintf(&src, "var (\n%s)\n\n", g.vars.String())
+	}
+	src.Write(g.body.Bytes())
+
+	return format.Source(src.Bytes())
+}
+
+// selectTypes returns the named structures to generate a Validate method for
+func (g *generator) selectTypes(pkg *types.Package, typeNames []string) ([]*types.Named, error) {
+	var named []*types.Named
+	if len(typeNames) > 0 {
+		for _, name := range typeNames {
+			obj, ok := pkg.Scope().Lookup(strings.TrimSpace(name)).(*types.TypeName)
+			if !ok {
+				return nil, fmt.Errorf("type %s not found", name)
+			}
+
+			t, ok := obj.Type().(*types.Named)
+			if !ok {
+				return nil, fmt.Errorf("type %s is not a named type", name)
+			}
+
+			if _, ok := t.Underlying().(*types.Struct); !ok {
+				return nil, fmt.Errorf("type %s is not a structure", name)
+			}
+			named = append(named, t)
+		}
+		return named, nil
+	}
+
+	for _, name := range pkg.Scope().Names() {
+		obj, ok := pkg.Scope().Lookup(name).(*types.TypeName)
+		if !ok || obj.IsAlias() {
+			continue
+		}
+
+		t, ok := obj.Type().(*types.Named)
+		if !ok {
+			continue
+		}
+
+		if st, ok := t.Underlying().(*types.Struct); ok && g.hasTags(st) {
+			named = append(named, t)
+		}
+	}
+	return named, nil
+}
+
+// hasTags reports whether one of the fields of the structure has validators
+func (g *generator) hasTags(st *types.Struct) bool {
+	for i := 0; i < st.NumFields(); i++ {
+		if tag := reflect.StructTag(st.Tag(i)).Get(g.tagName); tag != "" && tag != "-" {
+			return true
+		}
+	}
+	return false
+}
+
+// writeMethod writes the Validate method of the structure
+func (g *generator) writeMethod(t *types.Named) error {
+	typeName := t.Obj().Name()
+	st := t.Underlying().(*types.Struct)
+
+	fmt.Fprintf(&g.body, "// Validate validates the fields of %s based on the `%s` tags and returns\n", typeName, g.tagName)
+	fmt.Fprintf(&g.body, "// the first validation error found per field name.\n")
+	fmt.Fprintf(&g.body, "func (v *%s) Validate() error {\n", typeName)
+	fmt.Fprintf(&g.body, "\tvar errs validate.Errors\n\n")
+
+	var fallback []string
+	for i := 0; i < st.NumFields(); i++ {
+		f := st.Field(i)
+		structTag := reflect.StructTag(st.Tag(i))
+		tag := structTag.Get(g.tagName)
+		if tag == "-" || !unicode.IsUpper(rune(f.Name()[0])) {
+			continue
+		}
+
+		var params []tags.Param
+		if tag != "" {
+			var err error
+			if params, err = tags.Parse(tag); err != nil {
+				return fmt.Errorf("%s.%s: invalid tag %q: %s", typeName, f.Name(), tag, err)
+			}
+		}
+
+		fw := &fieldWriter{
+			g:     g,
+			field: f,
+			expr:  "v." + f.Name(),
+			ident: typeName + f.Name(),
+			path:  f.Name(),
+			name:  g.resolver(f.Name(), structTag),
+		}
+
+		if !fw.generated(params) {
+			fallback = append(fallback, strconv.Quote(f.Name()))
+			continue
+		}
+		fw.write(params)
+	}
+
+	if len(fallback) > 0 {
+		check := fmt.Sprintf("validate%sFallback", typeName)
+		fmt.Fprintf(&g.vars, "\t%s = validate.NewPartialCheck(%s, %s)\n", check, g.runtimeResolver, strings.Join(fallback, ", "))
+		fmt.Fprintf(&g.body, "\tif err := %s.Validate(v); err != nil {\n", check)
+		fmt.Fprintf(&g.body, "\t\terrs.Merge(err)\n\t}\n\n")
+	}
+
+	fmt.Fprintf(&g.body, "\tif errs == nil {\n\t\treturn nil\n\t}\n\treturn errs\n}\n\n")
+	return nil
+}
+
+// fieldWriter writes the validation of a single field
+type fieldWriter struct {
+	g     *generator
+	field *types.Var
+	expr  string // expression selecting the field
+	ident string // identifier used for the variables of the field
+	path  string // path of the field using the Go field names
+	name  string // resolved name of the field
+	vars  int    // number of variables written for the field
+}
+
+// generated reports whether the field can be validated by generated code, the
+// remaining fields are validated by the runtime
+func (fw *fieldWriter) generated(params []tags.Param) bool {
+	for _, p := range params {
+		if runtimeMarkers[p.Name] || parentValidators[p.Name] {
+			return false
+		}
+	}
+
+	t := fw.field.Type()
+	if !holdsStruct(t) || isOpaque(t) {
+		return true
+	}
+
+	// structures in slices, arrays and maps or structures without a generated
+	// method are left to the runtime
+	ptr, ok := t.(*types.Pointer)
+	if ok {
+		t = ptr.Elem()
+	}
+	named, ok := t.(*types.Named)
+	return ok && fw.g.generated[named]
+}
+
+// write writes a switch with a case per validator, the first failing validator
+// ends the validation of the field
+func (fw *fieldWriter) write(params []tags.Param) {
+	var cases bytes.Buffer
+	for _, p := range params {
+		cases.WriteString(fw.validatorCase(p))
+	}
+
+	nested := fw.nested()
+	if cases.Len() == 0 && nested == "" {
+		return
+	}
+
+	b := &fw.g.body
+	if cases.Len() == 0 {
+		fmt.Fprintf(b, "%s\n", nested)
+		return
+	}
+
+	fmt.Fprintf(b, "\tswitch {\n%s", cases.String())
+	if nested != "" {
+		fmt.Fprintf(b, "\tdefault:\n%s", nested)
+	}
+	fmt.Fprintf(b, "\t}\n\n")
+}
+
+// nested returns the code validating a nested structure with its generated method
+func (fw *fieldWriter) nested() string {
+	t := fw.field.Type()
+	if !holdsStruct(t) || isOpaque(t) {
+		return ""
+	}
+
+	call := fmt.Sprintf("\tvalidate.MergeNested(&errs, %s.Validate(), %q, %q)\n", fw.expr, fw.path+".", fw.name+".")
+	if _, ok := t.(*types.Pointer); ok {
+		return fmt.Sprintf("\tif %s != nil {\n%s\t}\n", fw.expr, call)
+	}
+	return call
+}
+
+// validatorCase returns the switch case for the validator
+func (fw *fieldWriter) validatorCase(p tags.Param) string {
+	t := fw.field.Type()
+
+	switch p.Name {
+	case "omitempty", "required":
+		if isStruct(t) {
+			// structures are never empty
+			return ""
+		}
+
+		cond, ok := zeroCondition(fw.expr, t)
+		if !ok {
+			break
+		}
+
+		if p.Name == "omitempty" {
+			return fmt.Sprintf("\tcase %s:\n\t\t// omitempty\n", cond)
+		}
+		return fw.failCase(cond, p)
+	case "len", "min", "max", "between":
+		if cond, ok := fw.sizeCondition(p); ok {
+			return fw.failCase(cond, p)
+		}
+	case "regexp":
+		if !isString(t) || len(p.Args) != 1 {
+			break
+		}
+
+		if _, err := regexp.Compile(p.Args[0]); err != nil {
+			break
+		}
+
+		fw.g.usesRegexp = true
+		re := fw.variable("Regexp", fmt.Sprintf("regexp.MustCompile(%q)", p.Args[0]))
+		return fw.failCase(fmt.Sprintf("!%s.MatchString(%s)", re, fw.expr), p)
+	case "in", "exclude":
+		if !isString(t) || len(p.Args) == 0 {
+			break
+		}
+
+		conds := make([]string, len(p.Args))
+		for i, arg := range p.Args {
+			conds[i] = fmt.Sprintf("%s == %q", fw.expr, arg)
+		}
+
+		cond := strings.Join(conds, " || ")
+		if p.Name == "in" {
+			cond = "!(" + cond + ")"
+		}
+		return fw.failCase(cond, p)
+	}
+
+	args := []string{strconv.Quote(p.Name)}
+	for _, arg := range p.Args {
+		args = append(args, strconv.Quote(arg))
+	}
+	check := fw.variable("Check", "validate.NewFieldCheck("+strings.Join(args, ", ")+")")
+	return fmt.Sprintf("\tcase %s.Check(&errs, %s, %q, %q):\n", check, fw.expr, fw.path, fw.name)
+}
+
+// failCase returns the case adding the error of the validator when the condition holds
+func (fw *fieldWriter) failCase(cond string, p tags.Param) string {
+	var params string
+	if len(p.Args) > 0 {
+		// every error gets its own params, callers can change them
+		params = " Params: " + paramsLiteral(p) + ","
+	}
+
+	return fmt.Sprintf("\tcase %s:\n\t\terrs.Add(%q, &validate.FieldError{Code: %q,%s Path: %q, Name: %q, Value: %s, Err: validate.%s})\n",
+		cond, fw.name, p.Name, params, fw.path, fw.name, fw.expr, sentinels[p.Name])
+}
+
+// paramsLiteral returns the slice literal holding the params of the validator
+func paramsLiteral(p tags.Param) string {
+	args := make([]string, len(p.Args))
+	for i, arg := range p.Args {
+		args[i] = strconv.Quote(arg)
+	}
+	return "[]string{" + strings.Join(args, ", ") + "}"
+}
+
+// variable writes a package level variable for the field and returns its name
+func (fw *fieldWriter) variable(kind, value string) string {
+	fw.vars++
+	name := fmt.Sprintf("validate%s%s%d", fw.ident, kind, fw.vars)
+	fmt.Fprintf(&fw.g.vars, "\t%s = %s\n", name, value)
+	return name
+}
+
+// sizeCondition returns the condition for the len, min, max and between
+// validators when the params are valid for the type of the field
+func (fw *fieldWriter) sizeCondition(p tags.Param) (string, bool) {
+	want := 1
+	if p.Name == "between" {
+		want = 2
+	}
+	if len(p.Args) != want {
+		return "", false
+	}
+
+	value, kind := sizeExpr(fw.expr, fw.field.Type())
+	if kind == "" {
+		return "", false
+	}
+
+	limits := make([]string, len(p.Args))
+	for i, arg := range p.Args {
+		switch kind {
+		case "int":
+			n, err := strconv.ParseInt(arg, 0, 64)
+			if err != nil {
+				return "", false
+			}
+			limits[i] = strconv.FormatInt(n, 10)
+		case "uint":
+			n, err := strconv.ParseUint(arg, 0, 64)
+			if err != nil {
+				return "", false
+			}
+			limits[i] = strconv.FormatUint(n, 10)
+		case "float":
+			n, err := strconv.ParseFloat(arg, 64)
+			if err != nil || math.IsInf(n, 0) || math.IsNaN(n) {
+				return "", false
+			}
+			limits[i] = strconv.FormatFloat(n, 'g', -1, 64)
+		}
+	}
+
+	switch p.Name {
+	case "len":
+		return fmt.Sprintf("%s != %s", value, limits[0]), true
+	case "min":
+		return fmt.Sprintf("%s < %s", value, limits[0]), true
+	case "max":
+		return fmt.Sprintf("%s > %s", value, limits[0]), true
+	}
+
+	// the limits of between are used in either order
+	lo, hi := limits[0], limits[1]
+	if greater(kind, p.Args[0], p.Args[1]) {
+		lo, hi = hi, lo
+	}
+	return fmt.Sprintf("%s < %s || %s > %s", value, lo, value, hi), true
+}
+
+// greater reports whether the parameter a is greater than b
+func greater(kind, a, b string) bool {
+	switch kind {
+	case "int":
+		x, _ := strconv.ParseInt(a, 0, 64)
+		y, _ := strconv.ParseInt(b, 0, 64)
+		return x > y
+	case "uint":
+		x, _ := strconv.ParseUint(a, 0, 64)
+		y, _ := strconv.ParseUint(b, 0, 64)
+		return x > y
+	}
+	x, _ := strconv.ParseFloat(a, 64)
+	y, _ := strconv.ParseFloat(b, 64)
+	return x > y
+}
+
+// sizeExpr returns the expression compared by the size validators and the kind
+// of its params, the kind is empty when the type is not supported
+func sizeExpr(expr string, t types.Type) (string, string) {
+	switch u := t.Underlying().(type) {
+	case *types.Slice, *types.Map, *types.Array:
+		return "int64(len(" + expr + "))", "int"
+	case *types.Basic:
+		switch {
+		case u.Info()&types.IsString != 0:
+			return "int64(len(" + expr + "))", "int"
+		case u.Info()&types.IsUnsigned != 0:
+			return "uint64(" + expr + ")", "uint"
+		case u.Info()&types.IsInteger != 0:
+			return "int64(" + expr + ")", "int"
+		case u.Info()&types.IsFloat != 0:
+			return "float64(" + expr + ")", "float"
+		}
+	}
+	return "", ""
+}
+
+// zeroCondition returns the condition that holds when the value is empty
+func zeroCondition(expr string, t types.Type) (string, bool) {
+	switch u := t.Underlying().(type) {
+	case *types.Pointer:
+		return expr + " == nil", true
+	case *types.Slice, *types.Map, *types.Array:
+		return "len(" + expr + ") == 0", true
+	case *types.Basic:
+		switch {
+		case u.Info()&types.IsString != 0:
+			return expr + ` == ""`, true
+		case u.Info()&types.IsBoolean != 0:
+			return "!" + expr, true
+		case u.Info()&(types.IsInteger|types.IsFloat) != 0:
+			return expr + " == 0", true
+		}
+	}
+	return "", false
+}
+
+// isString reports whether the type is the string type, named string types are
+// not supported by the string validators
+func isString(t types.Type) bool {
+	return types.Identical(t, types.Typ[types.String])
+}
+
+// isStruct reports whether the type is a structure
+func isStruct(t types.Type) bool {
+	_, ok := t.Underlying().(*types.Struct)
+	return ok
+}
+
+// isOpaque reports whether the type is a structure, or a pointer to one, with
+// nothing to validate. It has no exported fields and no Validate method like
+// time.Time.
+func isOpaque(t types.Type) bool {
+	if ptr, ok := t.Underlying().(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+
+	st, ok := t.Underlying().(*types.Struct)
+	if !ok {
+		return false
+	}
+
+	for i := 0; i < st.NumFields(); i++ {
+		if st.Field(i).Exported() {
+			return false
+		}
+	}
+
+	for _, method := range []string{"Validate", "ValidateCtx"} {
+		if obj, _, _ := types.LookupFieldOrMethod(t, true, nil, method); obj != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// holdsStruct reports whether a structure is found after unwrapping all the
+// pointer, slice, array and map layers of the type
+func holdsStruct(t types.Type) bool {
+	seen := map[types.Type]bool{}
+	for !seen[t] {
+		seen[t] = true
+		switch u := t.Underlying().(type) {
+		case *types.Pointer:
+			t = u.Elem()
+		case *types.Slice:
+			t = u.Elem()
+		case *types.Array:
+			t = u.Elem()
+		case *types.Map:
+			t = u.Elem()
+		case *types.Struct:
+			return true
+		default:
+			return false
+		}
+	}
+	return false
+}
+
+// jsonName resolves the name of the field like validate.JsonNameResolver
+func jsonName(name string, tag reflect.StructTag) string {
+	if jsonName := strings.SplitN(tag.Get("json"), ",", 2)[0]; jsonName != "" {
+		return jsonName
+	}
+	return name
+}
+
+// toSnakeCase converts the name like validate.JsonNameSnakeCaseResolver
+func toSnakeCase(in string) string {
+	runes := []rune(in)
+
+	var out []rune
+	for i := 0; i < len(runes); i++ {
+		if i > 0 && (unicode.IsUpper(runes[i]) || unicode.IsNumber(runes[i])) && ((i+1 < len(runes) && unicode.IsLower(runes[i+1])) || unicode.IsLower(runes[i-1])) {
+			out = append(out, '_')
+		}
+		out = append(out, unicode.ToLower(runes[i]))
+	}
+
+	return string(out)
+}
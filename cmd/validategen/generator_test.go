@@ -0,0 +1,34 @@
+package main
+
+import (
+	. "gopkg.in/check.v1"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+type GeneratorSuite struct{}
+
+var _ = Suite(&GeneratorSuite{})
+
+func (s *GeneratorSuite) TestGenerateExample(c *C) {
+	dir := filepath.Join("internal", "example")
+
+	g := &generator{tagName: "validate", resolver: nameResolvers["json"], runtimeResolver: runtimeResolvers["json"]}
+	src, err := g.generate(dir, "validate_gen.go", nil)
+	c.Assert(err, IsNil)
+
+	expected, err := os.ReadFile(filepath.Join(dir, "validate_gen.go"))
+	c.Assert(err, IsNil)
+	c.Assert(string(src), Equals, string(expected))
+}
+
+func (s *GeneratorSuite) TestGenerateUnknownType(c *C) {
+	g := &generator{tagName: "validate", resolver: nameResolvers["go"], runtimeResolver: runtimeResolvers["go"]}
+	_, err := g.generate(filepath.Join("internal", "example"), "validate_gen.go", []string{"Unknown"})
+	c.Assert(err, ErrorMatches, "type Unknown not found")
+}
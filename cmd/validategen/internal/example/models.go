@@ -0,0 +1,26 @@
+// Package example holds structures with a generated Validate method
+package example
+
+import "time"
+
+//go:generate go run github.com/mbict/go-validate/cmd/validategen -names=json
+
+type Address struct {
+	Street string `json:"street" validate:"required;min(3)"`
+	City   string `json:"city" validate:"required"`
+	Zip    string `json:"zip" validate:"omitempty;regexp(\"^[0-9]{4}[A-Z]{2}$\")"`
+}
+
+type User struct {
+	Name     string    `json:"name" validate:"required;between(3,40)"`
+	Email    string    `json:"email" validate:"required;email"`
+	Age      int       `json:"age" validate:"min(18);max(130)"`
+	Score    float64   `json:"score" validate:"max(9.5)"`
+	Role     string    `json:"role" validate:"in(admin,user)"`
+	Tags     []string  `json:"tags" validate:"max(3);dive;min(2)"`
+	Password string    `json:"password" validate:"required"`
+	Confirm  string    `json:"confirm" validate:"eqfield(Password)"`
+	Address  *Address  `json:"address" validate:"required"`
+	Billing  Address   `json:"billing"`
+	Created  time.Time `json:"created"`
+}
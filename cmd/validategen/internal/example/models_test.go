@@ -0,0 +1,73 @@
+package example_test
+
+import (
+	"github.com/mbict/go-validate"
+	"github.com/mbict/go-validate/cmd/validategen/internal/example"
+	. "gopkg.in/check.v1"
+	"testing"
+)
+
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+type ExampleSuite struct{}
+
+var _ = Suite(&ExampleSuite{})
+
+func (s *ExampleSuite) TestGeneratedMatchesRuntime(c *C) {
+	// the runtime names the errors like the generator with -names=json
+	validator := validate.NewValidator(validate.NameResolverOption(validate.JsonNameResolver))
+
+	users := []example.User{
+		{},
+		{
+			Name:     "john",
+			Email:    "john@example.com",
+			Age:      30,
+			Role:     "admin",
+			Password: "secret",
+			Confirm:  "secret",
+			Address:  &example.Address{Street: "Main street", City: "Amsterdam", Zip: "1234AB"},
+			Billing:  example.Address{Street: "Main street", City: "Amsterdam"},
+		},
+		{
+			Name:     "jo",
+			Email:    "john",
+			Age:      200,
+			Score:    10,
+			Role:     "guest",
+			Tags:     []string{"a", "bb", "c", "d"},
+			Password: "secret",
+			Confirm:  "other",
+			Address:  &example.Address{Street: "M", Zip: "1234"},
+			Billing:  example.Address{City: "Utrecht", Zip: "abc"},
+		},
+	}
+
+	for i := range users {
+		generated := users[i].Validate()
+		runtime := validator.Validate(users[i])
+
+		if runtime == nil {
+			c.Check(generated, IsNil, Commentf("user %d", i))
+			continue
+		}
+
+		c.Assert(generated, NotNil, Commentf("user %d", i))
+		c.Check(generated.Error(), Equals, runtime.Error(), Commentf("user %d", i))
+
+		generatedErrs := generated.(validate.Errors)
+		for name, errs := range runtime.(validate.Errors) {
+			for j, err := range errs {
+				expected := err.(*validate.FieldError)
+				fe, ok := generatedErrs[name][j].(*validate.FieldError)
+				c.Assert(ok, Equals, true)
+				c.Check(fe.Code, Equals, expected.Code)
+				c.Check(fe.Path, Equals, expected.Path)
+				c.Check(fe.Name, Equals, expected.Name)
+				c.Check(fe.Err, Equals, expected.Err)
+			}
+		}
+	}
+}
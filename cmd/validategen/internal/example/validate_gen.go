@@ -0,0 +1,105 @@
+// Code generated by validategen; DO NOT EDIT.
+
+package example
+
+import (
+	"github.com/mbict/go-validate"
+	"regexp"
+)
+
+var (
+	validateAddressZipRegexp1 = regexp.MustCompile("^[0-9]{4}[A-Z]{2}$")
+	validateUserEmailCheck1   = validate.NewFieldCheck("email")
+	validateUserFallback      = validate.NewPartialCheck(validate.JsonNameResolver, "Tags", "Confirm")
+)
+
+// Validate validates the fields of Address based on the `validate` tags and returns
+// the first validation error found per field name.
+func (v *Address) Validate() error {
+	var errs validate.Errors
+
+	switch {
+	case v.Street == "":
+		errs.Add("street", &validate.FieldError{Code: "required", Path: "Street", Name: "street", Value: v.Street, Err: validate.ErrRequired})
+	case int64(len(v.Street)) < 3:
+		errs.Add("street", &validate.FieldError{Code: "min", Params: []string{"3"}, Path: "Street", Name: "street", Value: v.Street, Err: validate.ErrMin})
+	}
+
+	switch {
+	case v.City == "":
+		errs.Add("city", &validate.FieldError{Code: "required", Path: "City", Name: "city", Value: v.City, Err: validate.ErrRequired})
+	}
+
+	switch {
+	case v.Zip == "":
+		// omitempty
+	case !validateAddressZipRegexp1.MatchString(v.Zip):
+		errs.Add("zip", &validate.FieldError{Code: "regexp", Params: []string{"^[0-9]{4}[A-Z]{2}$"}, Path: "Zip", Name: "zip", Value: v.Zip, Err: validate.ErrRegexp})
+	}
+
+	if errs == nil {
+		return nil
+	}
+	return errs
+}
+
+// Validate validates the fields of User based on the `validate` tags and returns
+// the first validation error found per field name.
+func (v *User) Validate() error {
+	var errs validate.Errors
+
+	switch {
+	case v.Name == "":
+		errs.Add("name", &validate.FieldError{Code: "required", Path: "Name", Name: "name", Value: v.Name, Err: validate.ErrRequired})
+	case int64(len(v.Name)) < 3 || int64(len(v.Name)) > 40:
+		errs.Add("name", &validate.FieldError{Code: "between", Params: []string{"3", "40"}, Path: "Name", Name: "name", Value: v.Name, Err: validate.ErrBetween})
+	}
+
+	switch {
+	case v.Email == "":
+		errs.Add("email", &validate.FieldError{Code: "required", Path: "Email", Name: "email", Value: v.Email, Err: validate.ErrRequired})
+	case validateUserEmailCheck1.Check(&errs, v.Email, "Email", "email"):
+	}
+
+	switch {
+	case int64(v.Age) < 18:
+		errs.Add("age", &validate.FieldError{Code: "min", Params: []string{"18"}, Path: "Age", Name: "age", Value: v.Age, Err: validate.ErrMin})
+	case int64(v.Age) > 130:
+		errs.Add("age", &validate.FieldError{Code: "max", Params: []string{"130"}, Path: "Age", Name: "age", Value: v.Age, Err: validate.ErrMax})
+	}
+
+	switch {
+	case float64(v.Score) > 9.5:
+		errs.Add("score", &validate.FieldError{Code: "max", Params: []string{"9.5"}, Path: "Score", Name: "score", Value: v.Score, Err: validate.ErrMax})
+	}
+
+	switch {
+	case !(v.Role == "admin" || v.Role == "user"):
+		errs.Add("role", &validate.FieldError{Code: "in", Params: []string{"admin", "user"}, Path: "Role", Name: "role", Value: v.Role, Err: validate.ErrInclude})
+	}
+
+	switch {
+	case v.Password == "":
+		errs.Add("password", &validate.FieldError{Code: "required", Path: "Password", Name: "password", Value: v.Password, Err: validate.ErrRequired})
+	}
+
+	switch {
+	case v.Address == nil:
+		errs.Add("address", &validate.FieldError{Code: "required", Path: "Address", Name: "address", Value: v.Address, Err: validate.ErrRequired})
+	default:
+		if v.Address != nil {
+			validate.MergeNested(&errs, v.Address.Validate(), "Address.", "address.")
+		}
+	}
+
+	validate.MergeNested(&errs, v.Billing.Validate(), "Billing.", "billing.")
+
+	if err := validateUserFallback.Validate(v); err != nil {
+		errs.Merge(err)
+	}
+
+	if errs == nil {
+		return nil
+	}
+	return errs
+}
@@ -0,0 +1,72 @@
+// Command validategen generates reflection free Validate methods for structures
+// based on their validate tags. Add a go generate directive to the package
+// holding the structures:
+//
+//	//go:generate validategen -type=User,Address -names=json
+//
+// The generated methods return the first error found per field in the same
+// Errors shape as validate.Validate. Validators that cannot be generated, like
+// validators registered with validate.SetValidationFunc, are run through the
+// default validator.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	typeNames = flag.String("type", "", "comma separated list of type names, all structures with tags when empty")
+	tagName   = flag.String("tag", "validate", "name of the structure tag holding the validators")
+	names     = flag.String("names", "go", "field names used in the errors: go, json or json_snake")
+	output    = flag.String("output", "", "output file name, default <dir>/validate_gen.go")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: validategen [flags] [directory]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	dir := "."
+	if flag.NArg() > 0 {
+		dir = flag.Arg(0)
+	}
+
+	outputName := *output
+	if outputName == "" {
+		outputName = filepath.Join(dir, "validate_gen.go")
+	}
+
+	var types []string
+	if *typeNames != "" {
+		types = strings.Split(*typeNames, ",")
+	}
+
+	g := &generator{
+		tagName:         *tagName,
+		resolver:        nameResolvers[*names],
+		runtimeResolver: runtimeResolvers[*names],
+	}
+	if g.resolver == nil {
+		fail(fmt.Errorf("unknown names %q", *names))
+	}
+
+	src, err := g.generate(dir, filepath.Base(outputName), types)
+	if err != nil {
+		fail(err)
+	}
+
+	if err := os.WriteFile(outputName, src, 0644); err != nil {
+		fail(err)
+	}
+}
+
+func fail(err error) {
+	fmt.Fprintf(os.Stderr, "validategen: %s\n", err)
+	os.Exit(1)
+}
@@ -0,0 +1,17 @@
+// Command validatelint reports broken validate struct tags, run it in CI to
+// catch them before the first request does:
+//
+//	validatelint -allow=postcode,iban ./...
+//
+// Custom validators registered with SetValidationFunc are reported as unknown
+// unless they are listed with the allow flag.
+package main
+
+import (
+	"github.com/mbict/go-validate/lint"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	singlechecker.Main(lint.Analyzer)
+}
@@ -0,0 +1,171 @@
+package validate
+
+import (
+	"github.com/mbict/go-errors"
+	"reflect"
+)
+
+// ErrFrozen is the error returned when the configuration of a frozen validator is changed
+var ErrFrozen = errors.New("validator is frozen")
+
+// config holds the configuration of a validator. A config is never changed once
+// it is in use, changes are made to a clone that replaces it. Validations that
+// are running keep using the config they started with.
+type config struct {
+	tagName                string                          // structure validatorTag name being used (`validate`)
+	validationFuncs        map[string]ValidatorFunc        // validator functions map indexed by name
+	contextValidationFuncs map[string]ContextValidatorFunc // context aware validator functions map indexed by name
+	compilers              map[string]CompilerFunc         // validator compilers map indexed by name
+	paramChecks            map[string]paramCheckFunc       // checks of the params against the type of the value indexed by name
+	fieldRules             map[reflect.Type]fieldRules     // field rules registered per structure
+	nameResolver           NameResolverFunc                // func to extract the name to use for field error
+	translator             *Translator                     // translator rendering the errors as messages
+}
+
+// clone returns a copy of the config that shares nothing that can be changed
+// through the validator
+func (cfg *config) clone() *config {
+	c := *cfg
+
+	c.validationFuncs = make(map[string]ValidatorFunc, len(cfg.validationFuncs))
+	for name, fn := range cfg.validationFuncs {
+		c.validationFuncs[name] = fn
+	}
+
+	c.contextValidationFuncs = make(map[string]ContextValidatorFunc, len(cfg.contextValidationFuncs))
+	for name, fn := range cfg.contextValidationFuncs {
+		c.contextValidationFuncs[name] = fn
+	}
+
+	c.compilers = make(map[string]CompilerFunc, len(cfg.compilers))
+	for name, fn := range cfg.compilers {
+		c.compilers[name] = fn
+	}
+
+	c.paramChecks = make(map[string]paramCheckFunc, len(cfg.paramChecks))
+	for name, fn := range cfg.paramChecks {
+		c.paramChecks[name] = fn
+	}
+
+	c.fieldRules = make(map[reflect.Type]fieldRules, len(cfg.fieldRules))
+	for t, rules := range cfg.fieldRules {
+		c.fieldRules[t] = make(fieldRules, len(rules))
+		for field, rule := range rules {
+			c.fieldRules[t][field] = rule
+		}
+	}
+	return &c
+}
+
+// removeValidator removes the validator functions registered with the name
+func (cfg *config) removeValidator(name string) {
+	delete(cfg.validationFuncs, name)
+	delete(cfg.contextValidationFuncs, name)
+	delete(cfg.compilers, name)
+	delete(cfg.paramChecks, name)
+}
+
+// checkParams checks the params of the validators against the type of the values
+// they validate, values of an interface type are checked when validated
+func (cfg *config) checkParams(validators []validatorTag, t reflect.Type) error {
+	if t.Kind() == reflect.Interface {
+		return nil
+	}
+
+	for _, v := range validators {
+		if check, ok := cfg.paramChecks[v.Name]; ok {
+			if err := check(v.Args, t); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// config returns the current configuration of the validator
+func (mv *validator) config() *config {
+	return mv.ruleCache().cfg
+}
+
+// ruleCache returns the rules cache of the current configuration
+func (mv *validator) ruleCache() *ruleCache {
+	return mv.cache.Load().(*ruleCache)
+}
+
+// configure applies the change to a clone of the configuration and replaces the
+// configuration together with an empty structure rules cache. The change is
+// rejected with ErrFrozen when the validator is frozen.
+func (mv *validator) configure(change func(cfg *config)) error {
+	return mv.tryConfigure(func(cfg *config) error {
+		change(cfg)
+		return nil
+	})
+}
+
+// tryConfigure applies the change like configure, the configuration is left
+// unchanged when the change fails
+func (mv *validator) tryConfigure(change func(cfg *config) error) error {
+	mv.mu.Lock()
+	defer mv.mu.Unlock()
+
+	if mv.frozen {
+		return ErrFrozen
+	}
+
+	cfg := mv.config().clone()
+	if err := change(cfg); err != nil {
+		return err
+	}
+	mv.cache.Store(newRuleCache(cfg))
+	return nil
+}
+
+// mustConfigure applies the change like configure and panics with ErrFrozen
+// when the validator is frozen, it is used by the setters without an error result
+func (mv *validator) mustConfigure(change func(cfg *config)) {
+	if err := mv.configure(change); err != nil {
+		panic(err)
+	}
+}
+
+// Freeze makes the configuration of the default validator immutable, see Validator.Freeze
+func Freeze() {
+	defaultValidator.Freeze()
+}
+
+// Clone creates an independent copy of the default validator
+func Clone() Validator {
+	return defaultValidator.Clone()
+}
+
+// Freeze makes the configuration of the validator immutable. Setters returning
+// an error return ErrFrozen afterwards, the others panic with ErrFrozen. The
+// translator is frozen as well, changing its messages panics. Freeze the
+// validator once it is set up at startup to catch changes made while
+// validating.
+func (mv *validator) Freeze() {
+	mv.mu.Lock()
+	defer mv.mu.Unlock()
+	mv.frozen = true
+	if translator := mv.config().translator; translator != nil {
+		translator.freeze()
+	}
+}
+
+// Clone creates a copy of the validator that shares nothing with the original,
+// changes to either of them do not affect the other. The copy is not frozen.
+func (mv *validator) Clone() Validator {
+	return mv.copy()
+}
+
+// copy creates a duplicate of the current validator and returns the new instance
+func (mv *validator) copy() *validator {
+	cfg := mv.config().clone()
+	if cfg.translator != nil {
+		cfg.translator = cfg.translator.clone()
+	}
+
+	v := &validator{}
+	v.cache.Store(newRuleCache(cfg))
+	return v
+}
@@ -0,0 +1,99 @@
+package validate_test
+
+import (
+	"github.com/mbict/go-validate"
+	. "gopkg.in/check.v1"
+	"strconv"
+	"sync"
+)
+
+type ConfigSuite struct{}
+
+var _ = Suite(&ConfigSuite{})
+
+func failing(v interface{}, params []string) error {
+	return validate.ErrInvalid
+}
+
+func (s *ConfigSuite) TestWithTagIsIndependent(c *C) {
+	type test struct {
+		A string `validate:"custom" other:"custom"`
+	}
+
+	original := validate.NewValidator()
+	c.Assert(original.SetValidationFunc("custom", func(v interface{}, params []string) error { return nil }), IsNil)
+	c.Assert(original.Validate(test{}), IsNil)
+
+	copied := original.WithTag("other")
+	c.Assert(copied.SetValidationFunc("custom", failing), IsNil)
+	c.Assert(copied.Validate(test{}), NotNil)
+
+	// the original keeps its own validator functions and cache
+	c.Assert(original.Validate(test{}), IsNil)
+
+	original.SetTag("unused")
+	c.Assert(copied.Validate(test{}), NotNil)
+}
+
+func (s *ConfigSuite) TestCloneIsIndependent(c *C) {
+	original := validate.NewValidator()
+	clone := original.Clone()
+
+	clone.Translator().SetMessage("en", "required", "{field} is missing")
+	c.Assert(clone.SetValidationFunc("required", failing), IsNil)
+
+	c.Assert(original.Valid("a", "required"), IsNil)
+	c.Assert(clone.Valid("a", "required"), NotNil)
+	c.Assert(original.Translator().Translate("en", validate.ErrRequired), Not(Equals), "{field} is missing")
+}
+
+func (s *ConfigSuite) TestFreeze(c *C) {
+	v := validate.NewValidator()
+	c.Assert(v.SetValidationFunc("custom", failing), IsNil)
+	v.Freeze()
+
+	c.Assert(v.SetValidationFunc("other", failing), Equals, validate.ErrFrozen)
+	c.Assert(v.SetContextValidationFunc("other", nil), Equals, validate.ErrFrozen)
+	c.Assert(v.SetStructRules(struct{ A string }{}, map[string]string{"A": "required"}), Equals, validate.ErrFrozen)
+	c.Assert(func() { v.SetTag("other") }, PanicMatches, "validator is frozen")
+	c.Assert(func() { v.SetNameResolver(validate.JsonNameResolver) }, PanicMatches, "validator is frozen")
+	c.Assert(func() { v.SetTranslator(validate.NewTranslator("en")) }, PanicMatches, "validator is frozen")
+	c.Assert(func() { v.Translator().SetMessage("en", "required", "{field} is missing") }, PanicMatches, "validator is frozen")
+	c.Assert(func() { v.Translator().AddCatalog("de", validate.Catalog{"required": "{field} fehlt"}) }, PanicMatches, "validator is frozen")
+	c.Assert(v.Valid("a", "custom"), NotNil)
+
+	// copies are not frozen
+	c.Assert(v.Clone().SetValidationFunc("other", failing), IsNil)
+	v.Clone().Translator().SetMessage("en", "required", "{field} is missing")
+	c.Assert(v.WithTag("other"), NotNil)
+}
+
+func (s *ConfigSuite) TestConcurrentConfiguration(c *C) {
+	type test struct {
+		A string `validate:"required;custom"`
+	}
+
+	v := validate.NewValidator()
+	c.Assert(v.SetValidationFunc("custom", failing), IsNil)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				v.SetValidationFunc("custom"+strconv.Itoa(i), failing)
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				v.Validate(test{A: "a"})
+				v.Valid("a", "custom")
+			}
+		}()
+	}
+	wg.Wait()
+
+	c.Assert(v.Validate(test{A: "a"}), NotNil)
+}
@@ -0,0 +1,269 @@
+package validate
+
+import (
+	"context"
+	"github.com/mbict/go-errors"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidSchema is the error returned when a data schema or one of its nested
+// schemas is nil, or uses a validator that refers to other fields
+var ErrInvalidSchema = errors.New("invalid data schema")
+
+// fieldValidators are the builtin validators referring to other fields of the
+// structure, untyped data has no structure to resolve the fields in
+var fieldValidators = map[string]bool{
+	"eqfield":          true,
+	"nefield":          true,
+	"gtfield":          true,
+	"ltfield":          true,
+	"required_if":      true,
+	"required_with":    true,
+	"required_without": true,
+	"excluded_if":      true,
+}
+
+// DataSchema describes the validators of untyped data, like decoded JSON
+// documents held in map[string]interface{} and []interface{} values.
+//
+// Fields maps the paths of the values to the validator tags in the format
+// accepted by Valid. A path is a dot separated list of object keys and array
+// indexes, * selects every element of an object or an array. Nested maps the
+// paths to the schemas of nested objects, the schema is applied to every
+// element when the value found is an array.
+//
+// Missing and null values are only validated by `required`, the other
+// validators are skipped like with omitempty. Validators referring to other
+// fields, like eqfield or required_if, are rejected with ErrInvalidSchema.
+type DataSchema struct {
+	Fields map[string]string
+	Nested map[string]*DataSchema
+}
+
+// compiledDataSchema holds the validators of a DataSchema parsed once per validation
+type compiledDataSchema struct {
+	fields []compiledDataField
+	nested []compiledNestedSchema
+}
+
+// compiledDataField holds the validators of a path
+type compiledDataField struct {
+	path       string
+	tag        string
+	validators []validatorTag // validators of present values
+	required   []validatorTag // validators of missing or null values
+}
+
+// compiledNestedSchema holds the schema of the nested objects found by the path
+type compiledNestedSchema struct {
+	path   string
+	schema *compiledDataSchema
+}
+
+// dataValue is a value found for a path in untyped data
+type dataValue struct {
+	path  string      // path of the value with the array indexes and object keys
+	value interface{} // value found, nil when the value is missing
+}
+
+// ValidateData validates untyped data against the schema of the default validator
+// and returns the first validation error found per path.
+func ValidateData(data interface{}, schema *DataSchema) error {
+	return defaultValidator.ValidateData(data, schema)
+}
+
+// ValidateAllData validates untyped data against the schema of the default validator
+// and returns all the validation errors found per path.
+func ValidateAllData(data interface{}, schema *DataSchema) error {
+	return defaultValidator.ValidateAllData(data, schema)
+}
+
+// ValidateData validates untyped data against the schema and returns the first
+// validation error found indexed per dotted path.
+func (mv *validator) ValidateData(data interface{}, schema *DataSchema) error {
+	return mv.validateData(context.Background(), data, schema, true)
+}
+
+// ValidateAllData validates untyped data against the schema and returns the
+// errors found indexed per dotted path.
+func (mv *validator) ValidateAllData(data interface{}, schema *DataSchema) error {
+	return mv.validateData(context.Background(), data, schema, false)
+}
+
+func (mv *validator) validateData(ctx context.Context, data interface{}, schema *DataSchema, stopOnError bool) error {
+	switch data.(type) {
+	case map[string]interface{}, []interface{}:
+	default:
+		return ErrUnsupported
+	}
+
+	compiled, err := mv.config().compileDataSchema(schema, groupsFromContext(ctx))
+	if err != nil {
+		return err
+	}
+
+	vs := &validation{ctx: ctx, stopOnError: stopOnError}
+	errs, err := compiled.validate(data, "", vs)
+	if err != nil {
+		return err
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// compileDataSchema parses the validators of the schema and its nested schemas
+func (cfg *config) compileDataSchema(schema *DataSchema, groups groupSet) (*compiledDataSchema, error) {
+	if schema == nil {
+		return nil, ErrInvalidSchema
+	}
+
+	compiled := &compiledDataSchema{}
+	for _, path := range sortedKeys(schema.Fields) {
+		validators, err := cfg.parseTags(schema.Fields[path], groups)
+		if err != nil {
+			return nil, err
+		}
+
+		field := compiledDataField{path: path, tag: schema.Fields[path], validators: validators}
+		for _, v := range validators {
+			if fieldValidators[v.Name] {
+				return nil, ErrInvalidSchema
+			}
+			if v.Name == "required" {
+				field.required = append(field.required, v)
+			}
+		}
+		compiled.fields = append(compiled.fields, field)
+	}
+
+	for _, path := range sortedKeys(schema.Nested) {
+		nested, err := cfg.compileDataSchema(schema.Nested[path], groups)
+		if err != nil {
+			return nil, err
+		}
+		compiled.nested = append(compiled.nested, compiledNestedSchema{path: path, schema: nested})
+	}
+	return compiled, nil
+}
+
+// validate validates the object against the schema, an array is validated
+// element by element. The prefix is prepended to the paths found.
+func (schema *compiledDataSchema) validate(data interface{}, prefix string, vs *validation) (Errors, error) {
+	var errs Errors
+	if elements, ok := data.([]interface{}); ok {
+		for i, element := range elements {
+			errv, err := schema.validate(element, prefix+strconv.Itoa(i)+".", vs)
+			if err != nil {
+				return nil, err
+			}
+			errs.Merge(errv)
+		}
+		return errs, nil
+	}
+
+	for _, field := range schema.fields {
+		for _, found := range lookupData(data, field.path) {
+			if err := vs.ctx.Err(); err != nil {
+				return nil, err
+			}
+
+			validators := field.validators
+			if found.value == nil {
+				validators = field.required
+			}
+
+			name := prefix + found.path
+			vs.field = &rule{Name: name, Tag: field.tag}
+			if verrs := runValidators(validators, found.value, name, name, vs); verrs != nil {
+				errs.Add(name, verrs...)
+			}
+		}
+	}
+
+	for _, nested := range schema.nested {
+		for _, found := range lookupData(data, nested.path) {
+			switch found.value.(type) {
+			case map[string]interface{}, []interface{}:
+			default:
+				// missing values are reported by the validators of the fields
+				continue
+			}
+
+			errv, err := nested.schema.validate(found.value, prefix+found.path+".", vs)
+			if err != nil {
+				return nil, err
+			}
+			errs.Merge(errv)
+		}
+	}
+	return errs, nil
+}
+
+// lookupData returns the values found for the dotted path, a missing value is
+// returned as nil unless the path selects every element with *
+func lookupData(data interface{}, path string) []dataValue {
+	found := []dataValue{{value: data}}
+	for _, segment := range strings.Split(path, ".") {
+		var next []dataValue
+		for _, current := range found {
+			next = append(next, lookupSegment(current, segment)...)
+		}
+		found = next
+	}
+	return found
+}
+
+// lookupSegment returns the values found for a single segment of a path
+func lookupSegment(current dataValue, segment string) []dataValue {
+	prefix := current.path
+	if prefix != "" {
+		prefix += "."
+	}
+
+	switch value := current.value.(type) {
+	case map[string]interface{}:
+		if segment != anyElement {
+			return []dataValue{{path: prefix + segment, value: value[segment]}}
+		}
+
+		found := make([]dataValue, 0, len(value))
+		for _, key := range sortedKeys(value) {
+			found = append(found, dataValue{path: prefix + key, value: value[key]})
+		}
+		return found
+	case []interface{}:
+		if segment != anyElement {
+			i, err := strconv.Atoi(segment)
+			if err != nil || i < 0 || i >= len(value) {
+				return []dataValue{{path: prefix + segment}}
+			}
+			return []dataValue{{path: prefix + segment, value: value[i]}}
+		}
+
+		found := make([]dataValue, len(value))
+		for i, element := range value {
+			found[i] = dataValue{path: prefix + strconv.Itoa(i), value: element}
+		}
+		return found
+	}
+
+	if segment == anyElement {
+		return nil
+	}
+	return []dataValue{{path: prefix + segment}}
+}
+
+// sortedKeys returns the keys of the map in a stable order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,138 @@
+package validate_test
+
+import (
+	"encoding/json"
+	"github.com/mbict/go-validate"
+	. "gopkg.in/check.v1"
+)
+
+type DataSuite struct{}
+
+var _ = Suite(&DataSuite{})
+
+var webhookSchema = &validate.DataSchema{
+	Fields: map[string]string{
+		"event":           "required;in(created,deleted)",
+		"order.id":        "required;uuid4",
+		"order.lines":     "required;min(1)",
+		"order.tags.*":    "max(5)",
+		"order.discounts": "omitempty;max(2)",
+	},
+	Nested: map[string]*validate.DataSchema{
+		"order.lines": {
+			Fields: map[string]string{
+				"sku":      "required",
+				"quantity": "min(1)",
+			},
+		},
+	},
+}
+
+func decodeData(c *C, data string) interface{} {
+	var v interface{}
+	c.Assert(json.Unmarshal([]byte(data), &v), IsNil)
+	return v
+}
+
+func (s *DataSuite) TestValidateData(c *C) {
+	data := decodeData(c, `{
+		"event": "created",
+		"order": {
+			"id": "a987fbc9-4bed-4078-8f07-9141ba07c9f3",
+			"lines": [{"sku": "A1", "quantity": 2}],
+			"tags": ["new", "promo"]
+		}
+	}`)
+
+	c.Assert(validate.ValidateData(data, webhookSchema), IsNil)
+}
+
+func (s *DataSuite) TestValidateAllDataErrors(c *C) {
+	data := decodeData(c, `{
+		"event": "updated",
+		"order": {
+			"lines": [{"sku": "A1", "quantity": 2}, {"quantity": 0}],
+			"tags": ["new", "promotion"]
+		}
+	}`)
+
+	err := validate.ValidateAllData(data, webhookSchema)
+	c.Assert(err, NotNil)
+
+	errs := err.(validate.Errors)
+	c.Assert(errs, HasLen, 5)
+	c.Assert(errs["event"], HasError, validate.ErrInclude)
+	c.Assert(errs["order.id"], HasError, validate.ErrRequired)
+	c.Assert(errs["order.tags.1"], HasError, validate.ErrMax)
+	c.Assert(errs["order.lines.1.sku"], HasError, validate.ErrRequired)
+	c.Assert(errs["order.lines.1.quantity"], HasError, validate.ErrMin)
+
+	fieldErr := errs["order.lines.1.quantity"][0].(*validate.FieldError)
+	c.Assert(fieldErr.Code, Equals, "min")
+	c.Assert(fieldErr.Name, Equals, "order.lines.1.quantity")
+}
+
+func (s *DataSuite) TestValidateDataArray(c *C) {
+	data := decodeData(c, `[{"name": "a"}, {}]`)
+
+	err := validate.ValidateData(data, &validate.DataSchema{
+		Fields: map[string]string{"name": "required"},
+	})
+	c.Assert(err, NotNil)
+	c.Assert(err.(validate.Errors)["1.name"], HasError, validate.ErrRequired)
+}
+
+func (s *DataSuite) TestValidateDataUnsupported(c *C) {
+	c.Assert(validate.ValidateData("test", &validate.DataSchema{}), Equals, validate.ErrUnsupported)
+
+	err := validate.ValidateData(map[string]interface{}{}, &validate.DataSchema{
+		Fields: map[string]string{"name": "unknown"},
+	})
+	c.Assert(err, NotNil)
+}
+
+func (s *DataSuite) TestValidateDataInvalidSchema(c *C) {
+	data := map[string]interface{}{"order": map[string]interface{}{}}
+	c.Assert(validate.ValidateData(data, nil), Equals, validate.ErrInvalidSchema)
+
+	err := validate.ValidateData(data, &validate.DataSchema{
+		Nested: map[string]*validate.DataSchema{"order": nil},
+	})
+	c.Assert(err, Equals, validate.ErrInvalidSchema)
+
+	// validators referring to other fields have no structure to resolve them in
+	data = map[string]interface{}{"type": "business", "company": "acme"}
+	err = validate.ValidateData(data, &validate.DataSchema{
+		Fields: map[string]string{"company": "required_if(type,business)"},
+	})
+	c.Assert(err, Equals, validate.ErrInvalidSchema)
+
+	err = validate.ValidateData(data, &validate.DataSchema{
+		Nested: map[string]*validate.DataSchema{"order": {Fields: map[string]string{"total": "gtfield(subtotal)"}}},
+	})
+	c.Assert(err, Equals, validate.ErrInvalidSchema)
+}
+
+func (s *DataSuite) TestValidateDataMissingValues(c *C) {
+	schema := &validate.DataSchema{
+		Fields: map[string]string{
+			"age":  "min(1)",
+			"name": "min(3);required",
+		},
+	}
+
+	// missing and null values are only validated by required
+	err := validate.ValidateAllData(decodeData(c, `{"name": null}`), schema)
+	c.Assert(err, NotNil)
+
+	errs := err.(validate.Errors)
+	c.Assert(errs, HasLen, 1)
+	c.Assert(errs["name"], HasLen, 1)
+	c.Assert(errs["name"], HasError, validate.ErrRequired)
+
+	c.Assert(validate.ValidateAllData(decodeData(c, `{"name": "john"}`), schema), IsNil)
+
+	err = validate.ValidateAllData(decodeData(c, `{"name": "john", "age": 0}`), schema)
+	c.Assert(err, NotNil)
+	c.Assert(err.(validate.Errors)["age"], HasError, validate.ErrMin)
+}
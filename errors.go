@@ -42,6 +42,30 @@ func NewValidationError(template string, args ...interface{}) ValidationError {
 	}
 }
 
+// FieldError describes a single failed validation of a value
+type FieldError struct {
+	Code   string      // name of the validator that failed (e.g. `min`)
+	Params []string    // parameters used for the validator (e.g. `3`)
+	Path   string      // path of the value using the Go field names (e.g. `Addresses.home.Street`)
+	Name   string      // path of the value using the resolved names as used in Errors
+	Value  interface{} // value that failed the validation
+	Err    error       // error returned by the validator
+}
+
+func (e *FieldError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the error returned by the validator so the error matches the
+// sentinel errors with errors.Is
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+func (e *FieldError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.Error())
+}
+
 var (
 	// errOmitEmpty is the error returned when variable has a empty value
 	errOmitEmpty = NewValidationError("omitempty")
@@ -159,6 +183,29 @@ var (
 
 	// ErrBEnum is the error returned when value is not in a set of enum values
 	ErrEnum = NewValidationError("invalid value")
+
+	// ErrEqField is the error returned when the value is not equal to the
+	// value of the field it is compared with
+	ErrEqField = NewValidationError("not equal to field")
+
+	// ErrNeField is the error returned when the value is equal to the
+	// value of the field it is compared with
+	ErrNeField = NewValidationError("equal to field")
+
+	// ErrGtField is the error returned when the value is not greater than
+	// the value of the field it is compared with
+	ErrGtField = NewValidationError("not greater than field")
+
+	// ErrLtField is the error returned when the value is not less than
+	// the value of the field it is compared with
+	ErrLtField = NewValidationError("not less than field")
+
+	// ErrExcluded is the error returned when the value is not empty while
+	// the field must be excluded
+	ErrExcluded = NewValidationError("excluded")
+
+	// errNilField is the error returned when one of the compared values is a nil pointer
+	errNilField = NewValidationError("nil field")
 )
 
 type ErrorList []error
@@ -0,0 +1,55 @@
+package validate
+
+import (
+	"context"
+	"reflect"
+	"sync"
+)
+
+// lockedValidator validates structures with the rules cache the validator used
+// before the lock-free cache, a map guarded by a sync.RWMutex where the rules
+// are compiled under the write lock. It is the baseline of the benchmarks.
+type lockedValidator struct {
+	validator *validator
+	mu        sync.RWMutex
+	rules     map[reflect.Type]*rules
+}
+
+// NewLockedValidator creates the baseline validator for the benchmarks
+func NewLockedValidator() interface{ Validate(v interface{}) error } {
+	return &lockedValidator{
+		validator: NewValidator().(*validator),
+		rules:     make(map[reflect.Type]*rules),
+	}
+}
+
+// Validate validates the structure like Validator.Validate
+func (lv *lockedValidator) Validate(v interface{}) error {
+	sv := reflect.Indirect(reflect.ValueOf(v))
+	if sv.Kind() != reflect.Struct {
+		return ErrUnsupported
+	}
+
+	lv.mu.RLock()
+	r, ok := lv.rules[sv.Type()]
+	lv.mu.RUnlock()
+	if !ok {
+		lv.mu.Lock()
+		compiled, pending, err := lv.validator.parseStruct(sv.Type(), groupSet{}, newRuleCache(lv.validator.config()))
+		if err != nil {
+			lv.mu.Unlock()
+			return err
+		}
+		for t, nested := range pending {
+			lv.rules[t] = nested
+		}
+		lv.mu.Unlock()
+		r = compiled
+	}
+
+	vs := &validation{ctx: context.Background(), stopOnError: true}
+	if errs := r.Validate(sv, vs); len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package validate
+
+import (
+	"context"
+	"reflect"
+)
+
+// FieldContext holds everything known about the value being validated and is
+// passed to a ContextValidatorFunc
+type FieldContext struct {
+	Context context.Context     // context of the validation run
+	Value   interface{}         // value being validated
+	Params  []string            // parameters used for the respective validation validatorTag
+	Field   reflect.StructField // struct field holding the value, empty when validating a single value
+	Tag     string              // full validatorTag of the field
+	Name    string              // name of the field as resolved by the NameResolverFunc
+	Path    string              // path of the value as used in Errors (e.g. `Addresses.home.Street`)
+	Parent  reflect.Value       // structure holding the field, invalid when validating a single value
+}
+
+// ContextValidatorFunc is a function that receives the value of a field together
+// with the field metadata, the parent structure and the context of the validation.
+type ContextValidatorFunc func(fc *FieldContext) error
+
+// fieldContext creates the FieldContext for a value found at the path
+func (vs *validation) fieldContext(v interface{}, params []string, path string) *FieldContext {
+	fc := &FieldContext{
+		Context: vs.ctx,
+		Value:   v,
+		Params:  params,
+		Path:    vs.prefix + path,
+		Parent:  vs.parent,
+	}
+
+	if vs.field != nil {
+		fc.Field = vs.field.Field
+		fc.Tag = vs.field.Tag
+		fc.Name = vs.field.Name
+	}
+	return fc
+}
+
+// fieldError wraps the error returned by the validator into a FieldError
+func (vs *validation) fieldError(t *validatorTag, v interface{}, path, goPath string, err error) error {
+	if _, ok := err.(*FieldError); ok {
+		return err
+	}
+
+	return &FieldError{
+		Code:   t.Name,
+		Params: append([]string(nil), t.Args...),
+		Path:   vs.goPrefix + goPath,
+		Name:   vs.prefix + path,
+		Value:  v,
+		Err:    err,
+	}
+}
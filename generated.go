@@ -0,0 +1,132 @@
+package validate
+
+import (
+	"context"
+	"github.com/mbict/go-tags"
+	"sync/atomic"
+)
+
+// FieldCheck runs a single validator of the default validator, it is used by the
+// Validate methods generated by validategen for the validators that are not
+// generated, like the validators registered with SetValidationFunc. The
+// validator is compiled once per configuration of the default validator.
+type FieldCheck struct {
+	code     string
+	params   []string
+	compiled atomic.Value // *compiledCheck for the last configuration used
+}
+
+// compiledCheck is the validator of a FieldCheck compiled for a configuration
+type compiledCheck struct {
+	cfg *config
+	tag *validatorTag
+	err error
+}
+
+// NewFieldCheck creates the check running the validator with the params
+func NewFieldCheck(code string, params ...string) *FieldCheck {
+	return &FieldCheck{code: code, params: params}
+}
+
+// Check validates the value and adds the error found to errs under the name. It
+// reports whether the remaining validators of the field are skipped, which is
+// the case when the validator failed or signalled the value can be omitted.
+func (fc *FieldCheck) Check(errs *Errors, v interface{}, path, name string) bool {
+	compiled := fc.compile(defaultValidator.(*validator).config())
+	if compiled.err != nil {
+		errs.Add(name, compiled.err)
+		return true
+	}
+
+	vs := &validation{ctx: context.Background(), field: &rule{Name: name}}
+	if err := compiled.tag.call(v, name, vs); err != nil {
+		if err != errOmitEmpty {
+			errs.Add(name, vs.fieldError(compiled.tag, v, name, path, err))
+		}
+		return true
+	}
+	return false
+}
+
+// compile returns the validator compiled for the configuration
+func (fc *FieldCheck) compile(cfg *config) *compiledCheck {
+	if compiled, ok := fc.compiled.Load().(*compiledCheck); ok && compiled.cfg == cfg {
+		return compiled
+	}
+
+	compiled := &compiledCheck{cfg: cfg}
+	validators, err := cfg.compileTags([]tags.Param{{Name: fc.code, Args: fc.params}})
+	if err != nil {
+		compiled.err = err
+	} else {
+		compiled.tag = &validators[0]
+	}
+	fc.compiled.Store(compiled)
+	return compiled
+}
+
+// PartialCheck validates the fields of a structure that are not generated with
+// the default validator, using the name resolver the Validate method was
+// generated with. It is used by the Validate methods generated by validategen.
+type PartialCheck struct {
+	resolver  NameResolverFunc
+	paths     []string
+	validator atomic.Value // *partialValidator for the last configuration used
+}
+
+// partialValidator is the validator of a PartialCheck derived from a configuration
+type partialValidator struct {
+	cfg       *config
+	validator *validator
+}
+
+// NewPartialCheck creates the check validating the fields found by the paths,
+// the errors are named with the resolver
+func NewPartialCheck(resolver NameResolverFunc, paths ...string) *PartialCheck {
+	return &PartialCheck{resolver: resolver, paths: paths}
+}
+
+// Validate validates the selected fields of the structure and returns the first
+// validation error found per field name
+func (pc *PartialCheck) Validate(v interface{}) error {
+	return pc.derive(defaultValidator.(*validator).config()).ValidatePartial(v, pc.paths...)
+}
+
+// derive returns the validator using the configuration with the name resolver
+// of the check, the rules it compiles are kept while the configuration is used
+func (pc *PartialCheck) derive(cfg *config) *validator {
+	if derived, ok := pc.validator.Load().(*partialValidator); ok && derived.cfg == cfg {
+		return derived.validator
+	}
+
+	derivedCfg := cfg.clone()
+	derivedCfg.nameResolver = pc.resolver
+
+	v := &validator{}
+	v.cache.Store(newRuleCache(derivedCfg))
+	pc.validator.Store(&partialValidator{cfg: cfg, validator: v})
+	return v
+}
+
+// MergeNested merges the errors of a nested structure validated by a generated
+// Validate method, the names and paths of the errors are prefixed with the name
+// and the path of the field holding the structure.
+func MergeNested(errs *Errors, err error, path, name string) {
+	nested, ok := err.(Errors)
+	if !ok {
+		if err != nil {
+			errs.MergePrefix(name, err)
+		}
+		return
+	}
+
+	for field, fieldErrs := range nested {
+		for _, fieldErr := range fieldErrs {
+			if fe, ok := fieldErr.(*FieldError); ok {
+				fe.Path = path + fe.Path
+				fe.Name = name + fe.Name
+			}
+		}
+		errs.Add(name+field, fieldErrs...)
+	}
+}
@@ -0,0 +1,49 @@
+package validate_test
+
+import (
+	"github.com/mbict/go-validate"
+	. "gopkg.in/check.v1"
+)
+
+type GeneratedSuite struct{}
+
+var _ = Suite(&GeneratedSuite{})
+
+func (s *GeneratedSuite) TestFieldCheck(c *C) {
+	check := validate.NewFieldCheck("min", "3")
+
+	var errs validate.Errors
+	c.Assert(check.Check(&errs, "abc", "Name", "name"), Equals, false)
+	c.Assert(errs, IsNil)
+
+	c.Assert(check.Check(&errs, "ab", "Name", "name"), Equals, true)
+	c.Assert(errs["name"], HasLen, 1)
+	c.Assert(errs["name"], HasError, validate.ErrMin)
+
+	// validators registered later on the default validator are used
+	code := validate.NewFieldCheck("generated_custom")
+	errs = nil
+	c.Assert(code.Check(&errs, "a", "Code", "code"), Equals, true)
+	c.Assert(errs["code"], HasError, validate.ErrUnknownTag)
+
+	c.Assert(validate.SetValidationFunc("generated_custom", failing), IsNil)
+	defer validate.SetValidationFunc("generated_custom", nil)
+
+	errs = nil
+	c.Assert(code.Check(&errs, "a", "Code", "code"), Equals, true)
+	c.Assert(errs["code"], HasError, validate.ErrInvalid)
+}
+
+func (s *GeneratedSuite) TestPartialCheck(c *C) {
+	type test struct {
+		Name  string `json:"name" validate:"required"`
+		Email string `json:"email" validate:"required"`
+	}
+
+	err := validate.NewPartialCheck(validate.JsonNameResolver, "Email").Validate(&test{})
+	c.Assert(err, NotNil)
+
+	errs := err.(validate.Errors)
+	c.Assert(errs, HasLen, 1)
+	c.Assert(errs["email"], HasError, validate.ErrRequired)
+}
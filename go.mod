@@ -0,0 +1,15 @@
+module github.com/mbict/go-validate
+
+go 1.25.0
+
+require (
+	golang.org/x/tools v0.47.0
+	gopkg.in/check.v1 v1.0.0-20201130134442-10cb98267c6c
+)
+
+require (
+	github.com/kr/pretty v0.2.1 // indirect
+	github.com/kr/text v0.1.0 // indirect
+	golang.org/x/mod v0.37.0 // indirect
+	golang.org/x/sync v0.21.0 // indirect
+)
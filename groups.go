@@ -0,0 +1,89 @@
+package validate
+
+import (
+	"context"
+	"github.com/mbict/go-tags"
+	"sort"
+	"strings"
+)
+
+// markers used within a validatorTag to scope validators to groups. Validators
+// after the group marker are only used when one of the groups in the parameters
+// is active, until the next group or the endgroup marker.
+const (
+	groupTag    = "group"
+	endGroupTag = "endgroup"
+)
+
+// groupsKey is the context key holding the active validation groups
+type groupsKey struct{}
+
+// WithGroups returns a copy of the context with the validation groups to use
+// when validating with ValidateCtx, ValidateAllCtx, ValidCtx or ValidAllCtx.
+// Validators outside of a group are always used.
+func WithGroups(ctx context.Context, groups ...string) context.Context {
+	return context.WithValue(ctx, groupsKey{}, newGroupSet(groups))
+}
+
+// groupSet holds the active validation groups
+type groupSet struct {
+	key    string // sorted and comma separated group names
+	active map[string]bool
+}
+
+// newGroupSet creates a set out of the group names
+func newGroupSet(groups []string) groupSet {
+	names := make([]string, 0, len(groups))
+	active := make(map[string]bool, len(groups))
+	for _, group := range groups {
+		if !active[group] {
+			active[group] = true
+			names = append(names, group)
+		}
+	}
+	sort.Strings(names)
+
+	return groupSet{
+		key:    strings.Join(names, ","),
+		active: active,
+	}
+}
+
+// groupsFromContext returns the active validation groups stored in the context
+func groupsFromContext(ctx context.Context) groupSet {
+	groups, _ := ctx.Value(groupsKey{}).(groupSet)
+	return groups
+}
+
+// filterGroups removes the group markers and the params scoped to groups that
+// are not active
+func (gs groupSet) filterGroups(params []tags.Param) ([]tags.Param, error) {
+	filtered := make([]tags.Param, 0, len(params))
+	inGroup, active := false, true
+	for _, param := range params {
+		switch param.Name {
+		case groupTag:
+			if len(param.Args) == 0 {
+				return nil, ErrInvalidParameterCount
+			}
+
+			inGroup, active = true, false
+			for _, group := range param.Args {
+				if gs.active[group] {
+					active = true
+					break
+				}
+			}
+		case endGroupTag:
+			if !inGroup {
+				return nil, ErrSyntax
+			}
+			inGroup, active = false, true
+		default:
+			if active {
+				filtered = append(filtered, param)
+			}
+		}
+	}
+	return filtered, nil
+}
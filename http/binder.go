@@ -0,0 +1,212 @@
+// Package http binds and validates incoming requests with a validate.Validator
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/mbict/go-validate"
+	"io"
+	"mime"
+	nethttp "net/http"
+	"reflect"
+	"strings"
+)
+
+const (
+	// defaultMaxMemory is the maximum memory used to parse multipart forms
+	defaultMaxMemory = 32 << 20
+
+	// defaultMaxBodySize is the maximum size of a request body
+	defaultMaxBodySize = 10 << 20
+)
+
+// ErrUnsupportedMediaType is the error returned when a request body has a
+// missing or unsupported content type
+var ErrUnsupportedMediaType = errors.New("unsupported media type")
+
+// ErrorHandler writes the response for a request that failed to bind
+type ErrorHandler func(w nethttp.ResponseWriter, r *nethttp.Request, err error)
+
+// Binder decodes requests into a target struct and validates the result
+type Binder struct {
+	validator    validate.Validator // validator used to validate the decoded values
+	errorHandler ErrorHandler       // handler writing the response when binding fails
+	maxMemory    int64              // maximum memory used to parse multipart forms
+	maxBodySize  int64              // maximum size of a request body, no limit when 0
+}
+
+type Option func(*Binder)
+
+// ValidatorOption sets the validator used to validate the decoded values
+func ValidatorOption(validator validate.Validator) Option {
+	return func(b *Binder) {
+		b.validator = validator
+	}
+}
+
+// ErrorHandlerOption sets the handler writing the response when binding fails
+func ErrorHandlerOption(errorHandler ErrorHandler) Option {
+	return func(b *Binder) {
+		b.errorHandler = errorHandler
+	}
+}
+
+// MaxMemoryOption sets the maximum memory used to parse multipart forms
+func MaxMemoryOption(maxMemory int64) Option {
+	return func(b *Binder) {
+		b.maxMemory = maxMemory
+	}
+}
+
+// MaxBodySizeOption sets the maximum size of a request body, larger bodies fail
+// to decode. A size of 0 removes the limit.
+func MaxBodySizeOption(maxBodySize int64) Option {
+	return func(b *Binder) {
+		b.maxBodySize = maxBodySize
+	}
+}
+
+// NewBinder creates a new Binder, by default the values are validated with a
+// new validate.Validator resolving the names from the json tags, the bodies are
+// limited to 10MB and failures are written as problem details
+func NewBinder(options ...Option) *Binder {
+	b := &Binder{
+		validator:   validate.NewValidator(validate.NameResolverOption(validate.JsonNameResolver)),
+		maxMemory:   defaultMaxMemory,
+		maxBodySize: defaultMaxBodySize,
+	}
+	b.errorHandler = b.writeError
+
+	for _, option := range options {
+		option(b)
+	}
+
+	return b
+}
+
+// Bind decodes the query parameters and the body of the request into the
+// target and validates the target with all the errors found. The body is
+// decoded as JSON or as a form based on the content type of the request.
+func (b *Binder) Bind(r *nethttp.Request, target interface{}) error {
+	if err := b.Decode(r, target); err != nil {
+		return err
+	}
+	return b.validator.ValidateAllCtx(r.Context(), target)
+}
+
+// Decode decodes the query parameters and the body of the request into the
+// target. A body with a missing or unsupported content type fails with
+// ErrUnsupportedMediaType.
+func (b *Binder) Decode(r *nethttp.Request, target interface{}) error {
+	if err := decodeValues(r.URL.Query(), target); err != nil {
+		return err
+	}
+
+	if r.Body == nil || r.Body == nethttp.NoBody {
+		return nil
+	}
+
+	if b.maxBodySize > 0 {
+		r.Body = nethttp.MaxBytesReader(nil, r.Body, b.maxBodySize)
+	}
+
+	contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch {
+	case contentType == "application/json" || strings.HasSuffix(contentType, "+json"):
+		if err := json.NewDecoder(r.Body).Decode(target); err != nil && err != io.EOF {
+			return &DecodeError{Err: err}
+		}
+	case contentType == "application/x-www-form-urlencoded":
+		if err := r.ParseForm(); err != nil {
+			return &DecodeError{Err: err}
+		}
+		return decodeValues(r.PostForm, target)
+	case contentType == "multipart/form-data":
+		if err := r.ParseMultipartForm(b.maxMemory); err != nil {
+			return &DecodeError{Err: err}
+		}
+		return decodeValues(r.PostForm, target)
+	default:
+		return &DecodeError{Err: ErrUnsupportedMediaType}
+	}
+	return nil
+}
+
+// Handle wraps the handler with a handler that binds every request into a new
+// value of the type of the prototype. The handler is only called when the
+// value is valid, the value is available with Value from the request context.
+// Requests that fail to bind are written by the ErrorHandler.
+func (b *Binder) Handle(prototype interface{}, next nethttp.Handler) nethttp.Handler {
+	t := reflect.TypeOf(prototype)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		target := reflect.New(t).Interface()
+		if err := b.Bind(r, target); err != nil {
+			b.errorHandler(w, r, err)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(WithValue(r.Context(), target)))
+	})
+}
+
+// HandleFunc wraps the handler function like Handle
+func (b *Binder) HandleFunc(prototype interface{}, next nethttp.HandlerFunc) nethttp.Handler {
+	return b.Handle(prototype, next)
+}
+
+// writeError writes the error as problem details, validation errors are
+// translated in the first language accepted by the request
+func (b *Binder) writeError(w nethttp.ResponseWriter, r *nethttp.Request, err error) {
+	switch e := err.(type) {
+	case validate.Errors:
+		validate.NewProblem(e,
+			validate.ProblemInstanceOption(r.URL.Path),
+			validate.ProblemTranslatorOption(b.validator.Translator(), acceptedLanguage(r)),
+		).Write(w)
+	case *DecodeError:
+		title, status := "Invalid request", nethttp.StatusBadRequest
+		var tooLarge *nethttp.MaxBytesError
+		if errors.Is(e, ErrUnsupportedMediaType) {
+			status = nethttp.StatusUnsupportedMediaType
+			title = nethttp.StatusText(status)
+		} else if errors.As(e, &tooLarge) {
+			status = nethttp.StatusRequestEntityTooLarge
+			title = nethttp.StatusText(status)
+		}
+
+		validate.NewProblem(nil,
+			validate.ProblemTitleOption(title),
+			validate.ProblemStatusOption(status),
+			validate.ProblemDetailOption(e.Error()),
+			validate.ProblemInstanceOption(r.URL.Path),
+		).Write(w)
+	default:
+		nethttp.Error(w, nethttp.StatusText(nethttp.StatusInternalServerError), nethttp.StatusInternalServerError)
+	}
+}
+
+// acceptedLanguage returns the first language of the Accept-Language header
+func acceptedLanguage(r *nethttp.Request) string {
+	language := r.Header.Get("Accept-Language")
+	if i := strings.IndexAny(language, ",;"); i >= 0 {
+		language = language[:i]
+	}
+	return strings.TrimSpace(language)
+}
+
+// valueKey is the context key holding the bound value
+type valueKey struct{}
+
+// WithValue returns a copy of the context holding the bound value
+func WithValue(ctx context.Context, v interface{}) context.Context {
+	return context.WithValue(ctx, valueKey{}, v)
+}
+
+// Value returns the bound value stored in the context, nil when there is none
+func Value(ctx context.Context) interface{} {
+	return ctx.Value(valueKey{})
+}
@@ -0,0 +1,151 @@
+package http_test
+
+import (
+	"encoding/json"
+	validatehttp "github.com/mbict/go-validate/http"
+	. "gopkg.in/check.v1"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+type BinderSuite struct{}
+
+var _ = Suite(&BinderSuite{})
+
+type testAddress struct {
+	City string `json:"city" validate:"required"`
+}
+
+type testUser struct {
+	Name    string       `json:"name" validate:"required;min(3)"`
+	Age     int          `json:"age" validate:"between(18,99)"`
+	Tags    []string     `json:"tags" form:"tag"`
+	Address *testAddress `json:"address"`
+}
+
+func (s *BinderSuite) TestBindJSON(c *C) {
+	r := httptest.NewRequest("POST", "/users?age=30", strings.NewReader(`{"name":"john","address":{"city":"Amsterdam"}}`))
+	r.Header.Set("Content-Type", "application/json")
+
+	var user testUser
+	err := validatehttp.NewBinder().Bind(r, &user)
+	c.Assert(err, IsNil)
+	c.Assert(user.Name, Equals, "john")
+	c.Assert(user.Age, Equals, 30)
+	c.Assert(user.Address.City, Equals, "Amsterdam")
+}
+
+func (s *BinderSuite) TestBindForm(c *C) {
+	form := url.Values{"name": {"jo"}, "age": {"30"}, "tag": {"a", "b"}, "address.city": {"Utrecht"}}
+	r := httptest.NewRequest("POST", "/users", strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var user testUser
+	err := validatehttp.NewBinder().Bind(r, &user)
+	c.Assert(err, NotNil)
+	c.Assert(err, ErrorMatches, `name: \[less than min\]`)
+	c.Assert(user.Tags, DeepEquals, []string{"a", "b"})
+	c.Assert(user.Address.City, Equals, "Utrecht")
+}
+
+func (s *BinderSuite) TestBindQueryDecodeError(c *C) {
+	r := httptest.NewRequest("GET", "/users?age=old", nil)
+
+	var user testUser
+	err := validatehttp.NewBinder().Bind(r, &user)
+	c.Assert(err, NotNil)
+
+	decodeErr, ok := err.(*validatehttp.DecodeError)
+	c.Assert(ok, Equals, true)
+	c.Assert(decodeErr.Field, Equals, "age")
+}
+
+func (s *BinderSuite) TestHandle(c *C) {
+	var bound *testUser
+	handler := validatehttp.NewBinder().HandleFunc(testUser{}, func(w http.ResponseWriter, r *http.Request) {
+		bound = validatehttp.Value(r.Context()).(*testUser)
+	})
+
+	r := httptest.NewRequest("GET", "/users?name=john&age=40", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, r)
+	c.Assert(rec.Code, Equals, http.StatusOK)
+	c.Assert(bound, NotNil)
+	c.Assert(bound.Name, Equals, "john")
+
+	// invalid values are written as problem details
+	bound = nil
+	r = httptest.NewRequest("GET", "/users?age=40", nil)
+	r.Header.Set("Accept-Language", "nl-NL,nl;q=0.9")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, r)
+	c.Assert(bound, IsNil)
+	c.Assert(rec.Code, Equals, http.StatusUnprocessableEntity)
+	c.Assert(rec.Header().Get("Content-Type"), Equals, "application/problem+json")
+
+	var problem map[string]interface{}
+	c.Assert(json.Unmarshal(rec.Body.Bytes(), &problem), IsNil)
+	c.Assert(problem["instance"], Equals, "/users")
+	c.Assert(problem["invalid-params"], DeepEquals, []interface{}{
+		map[string]interface{}{"name": "name", "reason": "name is verplicht", "code": "required", "pointer": "/name"},
+		map[string]interface{}{"name": "name", "reason": "name moet minimaal 3 zijn", "code": "min", "pointer": "/name"},
+	})
+
+	// malformed bodies are a bad request
+	r = httptest.NewRequest("POST", "/users", strings.NewReader(`{`))
+	r.Header.Set("Content-Type", "application/json")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, r)
+	c.Assert(rec.Code, Equals, http.StatusBadRequest)
+
+	// bodies without a supported content type are rejected
+	r = httptest.NewRequest("POST", "/users", strings.NewReader(`{"name":"john"}`))
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, r)
+	c.Assert(rec.Code, Equals, http.StatusUnsupportedMediaType)
+
+	r = httptest.NewRequest("POST", "/users", strings.NewReader(`name: john`))
+	r.Header.Set("Content-Type", "text/yaml")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, r)
+	c.Assert(rec.Code, Equals, http.StatusUnsupportedMediaType)
+}
+
+func (s *BinderSuite) TestMaxBodySizeOption(c *C) {
+	binder := validatehttp.NewBinder(validatehttp.MaxBodySizeOption(32))
+	handler := binder.HandleFunc(testUser{}, func(w http.ResponseWriter, r *http.Request) {})
+
+	r := httptest.NewRequest("POST", "/users", strings.NewReader(`{"name":"john","age":30,"tags":["a","b","c"]}`))
+	r.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, r)
+	c.Assert(rec.Code, Equals, http.StatusRequestEntityTooLarge)
+
+	r = httptest.NewRequest("POST", "/users", strings.NewReader(`{"name":"john","age":30}`))
+	r.Header.Set("Content-Type", "application/json")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, r)
+	c.Assert(rec.Code, Equals, http.StatusOK)
+}
+
+func (s *BinderSuite) TestErrorHandlerOption(c *C) {
+	var handled error
+	binder := validatehttp.NewBinder(validatehttp.ErrorHandlerOption(func(w http.ResponseWriter, r *http.Request, err error) {
+		handled = err
+		w.WriteHeader(http.StatusTeapot)
+	}))
+
+	handler := binder.HandleFunc(&testUser{}, func(w http.ResponseWriter, r *http.Request) {})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/users", nil))
+	c.Assert(rec.Code, Equals, http.StatusTeapot)
+	c.Assert(handled, NotNil)
+}
@@ -0,0 +1,205 @@
+package http
+
+import (
+	"encoding"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// DecodeError is the error returned when the request cannot be decoded
+type DecodeError struct {
+	Field string // name of the form or query parameter, empty when decoding the body
+	Err   error  // error found while decoding
+}
+
+func (e *DecodeError) Error() string {
+	if e.Field == "" {
+		return "invalid request body: " + e.Err.Error()
+	}
+	return "invalid value for " + e.Field + ": " + e.Err.Error()
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// decodeValues decodes form or query values into the target. A field is found
+// by the name in its `form` tag, the name in its `json` tag or the field name,
+// fields of nested structures are found by their dotted names.
+func decodeValues(values url.Values, target interface{}) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return nil
+	}
+	return decodeStruct(values, v.Elem(), "")
+}
+
+// decodeStruct decodes the values found with the prefix into the fields of the structure
+func decodeStruct(values url.Values, v reflect.Value, prefix string) error {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		name := fieldName(field)
+		if name == "-" {
+			continue
+		}
+
+		fv := v.Field(i)
+		if field.Anonymous && name == field.Name {
+			if err := decodeStruct(values, fv, prefix); err != nil {
+				return err
+			}
+			continue
+		}
+
+		key := prefix + name
+		if isStruct(field.Type) {
+			if hasPrefix(values, key+".") {
+				if err := decodeStruct(values, fv, key+"."); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		if vals, ok := values[key]; ok {
+			if err := decodeField(fv, vals); err != nil {
+				return &DecodeError{Field: key, Err: err}
+			}
+		}
+	}
+	return nil
+}
+
+// decodeField decodes the values into the field, slices take all the values
+// while other fields take the first value
+func decodeField(v reflect.Value, vals []string) error {
+	if v.Kind() == reflect.Slice && v.Type().Elem().Kind() != reflect.Uint8 {
+		slice := reflect.MakeSlice(v.Type(), len(vals), len(vals))
+		for i, val := range vals {
+			if err := decodeString(slice.Index(i), val); err != nil {
+				return err
+			}
+		}
+		v.Set(slice)
+		return nil
+	}
+
+	if len(vals) == 0 {
+		return nil
+	}
+	return decodeString(v, vals[0])
+}
+
+// decodeString decodes a single value into v
+func decodeString(v reflect.Value, s string) error {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return decodeString(v.Elem(), s)
+	}
+
+	if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(s))
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		// []byte
+		v.SetBytes([]byte(s))
+	default:
+		return &unsupportedTypeError{typ: v.Type()}
+	}
+	return nil
+}
+
+// unsupportedTypeError is the error returned for fields that cannot be decoded from a string
+type unsupportedTypeError struct {
+	typ reflect.Type
+}
+
+func (e *unsupportedTypeError) Error() string {
+	return "unsupported type " + e.typ.String()
+}
+
+// fieldName returns the name of the field as used in forms and query strings
+func fieldName(field reflect.StructField) string {
+	for _, tag := range []string{"form", "json"} {
+		if name := strings.Split(field.Tag.Get(tag), ",")[0]; name != "" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+// isStruct reports whether values of the type hold a structure that is decoded
+// field by field
+func isStruct(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	return !reflect.PtrTo(t).Implements(textUnmarshalerType)
+}
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// hasPrefix reports whether one of the value names starts with the prefix
+func hasPrefix(values url.Values, prefix string) bool {
+	for name := range values {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
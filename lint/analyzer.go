@@ -0,0 +1,376 @@
+// Package lint provides an analyzer reporting broken validate struct tags, like
+// unknown validators, bad parameters, validators used on kinds they do not
+// support and invalid regular expressions.
+package lint
+
+import (
+	"github.com/mbict/go-tags"
+	"go/ast"
+	"go/types"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Analyzer reports broken validate struct tags
+var Analyzer = &analysis.Analyzer{
+	Name:     "validatelint",
+	Doc:      "check validate struct tags for unknown validators, bad parameters, unsupported kinds and invalid regexps",
+	Run:      run,
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+}
+
+var (
+	tagName string // name of the struct tag holding the validators
+	allow   string // comma separated list of custom validators
+)
+
+func init() {
+	Analyzer.Flags.StringVar(&tagName, "tag", "validate", "name of the struct tag holding the validators")
+	Analyzer.Flags.StringVar(&allow, "allow", "", "comma separated list of custom validator names that are allowed")
+}
+
+// markers used within a tag, see the validate package
+const (
+	diveTag     = "dive"
+	keysTag     = "keys"
+	endKeysTag  = "endkeys"
+	groupTag    = "group"
+	endGroupTag = "endgroup"
+)
+
+// param types of the validators
+const (
+	anyParam    = iota // any value
+	numberParam        // number, an integer for strings, slices, arrays and maps
+	regexpParam        // regular expression
+)
+
+// kinds of values the validators support
+const (
+	anyKind      = 0
+	stringKind   = 1 << iota // strings
+	numberKind               // integers and floats
+	lengthKind               // slices, arrays and maps
+	stringerKind             // types implementing fmt.Stringer
+	stringsKind              // slices of strings
+	pointerKind              // pointers to strings
+)
+
+// validatorSpec describes the parameters and the kinds supported by a validator
+type validatorSpec struct {
+	minParams int // minimum number of parameters
+	maxParams int // maximum number of parameters, -1 for no maximum
+	param     int // type of the parameters
+	kinds     int // supported kinds, anyKind for every kind
+}
+
+// validators describes the builtin validators of validate.NewValidator
+var validators = map[string]validatorSpec{
+	"omitempty":        {},
+	"required":         {},
+	"not_empty":        {kinds: stringKind | stringerKind | pointerKind},
+	"in":               {minParams: 1, maxParams: -1},
+	"exclude":          {minParams: 1, maxParams: -1},
+	"enum":             {minParams: 0, maxParams: -1, kinds: stringKind | stringsKind},
+	"url":              {kinds: stringKind},
+	"email":            {kinds: stringKind},
+	"numeric":          {kinds: stringKind},
+	"number":           {kinds: stringKind},
+	"identifier":       {kinds: stringKind},
+	"alpha_dash":       {kinds: stringKind},
+	"alpha_dash_dot":   {kinds: stringKind},
+	"alpha":            {kinds: stringKind},
+	"alphanumeric":     {kinds: stringKind},
+	"uuid":             {kinds: stringKind | stringerKind},
+	"uuid3":            {kinds: stringKind | stringerKind},
+	"uuid4":            {kinds: stringKind | stringerKind},
+	"uuid5":            {kinds: stringKind | stringerKind},
+	"base64":           {kinds: stringKind},
+	"len":              {minParams: 1, maxParams: 1, param: numberParam, kinds: stringKind | numberKind | lengthKind},
+	"min":              {minParams: 1, maxParams: 1, param: numberParam, kinds: stringKind | numberKind | lengthKind},
+	"max":              {minParams: 1, maxParams: 1, param: numberParam, kinds: stringKind | numberKind | lengthKind},
+	"between":          {minParams: 2, maxParams: 2, param: numberParam, kinds: stringKind | numberKind | lengthKind},
+	"around":           {minParams: 2, maxParams: 2, param: numberParam, kinds: stringKind | numberKind | lengthKind},
+	"regexp":           {minParams: 1, maxParams: 1, param: regexpParam, kinds: stringKind},
+	"eqfield":          {minParams: 1, maxParams: 1},
+	"nefield":          {minParams: 1, maxParams: 1},
+	"gtfield":          {minParams: 1, maxParams: 1},
+	"ltfield":          {minParams: 1, maxParams: 1},
+	"required_if":      {minParams: 2, maxParams: -1},
+	"required_with":    {minParams: 1, maxParams: -1},
+	"required_without": {minParams: 1, maxParams: -1},
+	"excluded_if":      {minParams: 2, maxParams: -1},
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	allowed := map[string]bool{}
+	for _, name := range strings.Split(allow, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			allowed[name] = true
+		}
+	}
+
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	inspect.Preorder([]ast.Node{(*ast.StructType)(nil)}, func(n ast.Node) {
+		for _, field := range n.(*ast.StructType).Fields.List {
+			if field.Tag == nil {
+				continue
+			}
+
+			value, err := strconv.Unquote(field.Tag.Value)
+			if err != nil {
+				continue
+			}
+
+			tag, ok := reflect.StructTag(value).Lookup(tagName)
+			if !ok || tag == "-" {
+				continue
+			}
+
+			c := &checker{
+				pass:    pass,
+				field:   field,
+				allowed: allowed,
+			}
+			c.check(tag, pass.TypesInfo.TypeOf(field.Type))
+		}
+	})
+	return nil, nil
+}
+
+// checker checks the tag of a single field
+type checker struct {
+	pass    *analysis.Pass
+	field   *ast.Field
+	allowed map[string]bool
+}
+
+func (c *checker) report(format string, args ...interface{}) {
+	c.pass.Reportf(c.field.Tag.Pos(), format, args...)
+}
+
+// check checks the validators of the tag against the type of the field
+func (c *checker) check(tag string, t types.Type) {
+	params, err := tags.Parse(tag)
+	if err != nil {
+		c.report("invalid %s tag syntax %q", tagName, tag)
+		return
+	}
+
+	current, fieldType := t, t
+	inKeys, inGroup := false, false
+	for _, p := range params {
+		switch p.Name {
+		case groupTag:
+			if len(p.Args) == 0 {
+				c.report("%s needs at least one group name", groupTag)
+			}
+			inGroup = true
+			continue
+		case endGroupTag:
+			if !inGroup {
+				c.report("%s without %s", endGroupTag, groupTag)
+			}
+			inGroup = false
+			continue
+		case keysTag:
+			if inKeys {
+				c.report("%s inside %s", keysTag, keysTag)
+				return
+			}
+
+			m, ok := deref(current).Underlying().(*types.Map)
+			if !ok {
+				c.report("%s used on %s, expected a map", keysTag, current)
+				return
+			}
+			inKeys, fieldType, current = true, current, m.Key()
+			continue
+		case endKeysTag:
+			if !inKeys {
+				c.report("%s without %s", endKeysTag, keysTag)
+				return
+			}
+			inKeys, current = false, fieldType
+			continue
+		case diveTag:
+			if inKeys {
+				c.report("%s inside %s", diveTag, keysTag)
+				return
+			}
+
+			elem := elemType(current)
+			if elem == nil {
+				c.report("%s used on %s, expected a slice, array or map", diveTag, current)
+				return
+			}
+			current, fieldType = elem, elem
+			continue
+		}
+
+		c.checkValidator(p, current)
+	}
+
+	if inKeys {
+		c.report("%s without %s", keysTag, endKeysTag)
+	}
+}
+
+// checkValidator checks the parameters and the kind of the value for the validator
+func (c *checker) checkValidator(p tags.Param, t types.Type) {
+	spec, ok := validators[p.Name]
+	if !ok {
+		if !c.allowed[p.Name] {
+			c.report("unknown validator %q", p.Name)
+		}
+		return
+	}
+
+	if len(p.Args) < spec.minParams || (spec.maxParams >= 0 && len(p.Args) > spec.maxParams) {
+		c.report("%s expects %s, got %d", p.Name, expectedParams(spec), len(p.Args))
+		return
+	}
+
+	if spec.kinds != anyKind && t != nil && !supportsKind(spec.kinds, t) {
+		c.report("%s does not support %s", p.Name, t)
+		return
+	}
+
+	for _, arg := range p.Args {
+		switch spec.param {
+		case numberParam:
+			if !validNumber(arg, t) {
+				c.report("%s has bad parameter %q for %s", p.Name, arg, t)
+			}
+		case regexpParam:
+			if _, err := regexp.Compile(arg); err != nil {
+				c.report("%s has invalid regexp %q: %s", p.Name, arg, err)
+			}
+		}
+	}
+}
+
+// expectedParams describes the number of parameters expected
+func expectedParams(spec validatorSpec) string {
+	switch {
+	case spec.maxParams == 0:
+		return "no parameters"
+	case spec.maxParams < 0:
+		return "at least " + plural(spec.minParams)
+	case spec.minParams == spec.maxParams:
+		return plural(spec.minParams)
+	}
+	return strconv.Itoa(spec.minParams) + " to " + plural(spec.maxParams)
+}
+
+func plural(n int) string {
+	if n == 1 {
+		return "1 parameter"
+	}
+	return strconv.Itoa(n) + " parameters"
+}
+
+// supportsKind reports whether the type is one of the supported kinds. The
+// validators receive the value of the field as is, pointers are not followed.
+func supportsKind(kinds int, t types.Type) bool {
+	if kinds&stringerKind != 0 && isStringer(t) {
+		return true
+	}
+
+	switch u := t.Underlying().(type) {
+	case *types.Interface:
+		// the dynamic type is unknown
+		return true
+	case *types.Basic:
+		switch {
+		case u.Info()&types.IsString != 0:
+			return kinds&stringKind != 0
+		case u.Info()&types.IsNumeric != 0:
+			return kinds&numberKind != 0
+		}
+	case *types.Slice:
+		if kinds&stringsKind != 0 && types.Identical(u.Elem(), types.Typ[types.String]) {
+			return true
+		}
+		return kinds&lengthKind != 0
+	case *types.Array, *types.Map:
+		return kinds&lengthKind != 0
+	case *types.Pointer:
+		if b, ok := u.Elem().(*types.Basic); ok && b.Kind() == types.String {
+			return kinds&pointerKind != 0
+		}
+	}
+	return false
+}
+
+// validNumber reports whether the parameter is a number for the type, the
+// length of strings, slices, arrays and maps is compared with integers
+func validNumber(arg string, t types.Type) bool {
+	_, intErr := strconv.ParseInt(arg, 0, 64)
+	_, uintErr := strconv.ParseUint(arg, 0, 64)
+	_, floatErr := strconv.ParseFloat(arg, 64)
+
+	var u types.Type
+	if t != nil {
+		u = t.Underlying()
+	}
+
+	if b, ok := u.(*types.Basic); ok {
+		switch {
+		case b.Info()&types.IsFloat != 0:
+			return floatErr == nil
+		case b.Info()&types.IsUnsigned != 0:
+			return uintErr == nil
+		case b.Info()&types.IsNumeric != 0, b.Info()&types.IsString != 0:
+			return intErr == nil
+		}
+	}
+
+	switch u.(type) {
+	case *types.Slice, *types.Array, *types.Map:
+		return intErr == nil
+	}
+	return intErr == nil || uintErr == nil || floatErr == nil
+}
+
+// isStringer reports whether the type implements fmt.Stringer
+func isStringer(t types.Type) bool {
+	obj, _, _ := types.LookupFieldOrMethod(t, false, nil, "String")
+	fn, ok := obj.(*types.Func)
+	if !ok {
+		return false
+	}
+
+	sig := fn.Type().(*types.Signature)
+	return sig.Params().Len() == 0 && sig.Results().Len() == 1 &&
+		types.Identical(sig.Results().At(0).Type(), types.Typ[types.String])
+}
+
+// deref returns the type the pointers point to
+func deref(t types.Type) types.Type {
+	for {
+		p, ok := t.Underlying().(*types.Pointer)
+		if !ok {
+			return t
+		}
+		t = p.Elem()
+	}
+}
+
+// elemType returns the element type of a slice, array or map, or nil
+func elemType(t types.Type) types.Type {
+	switch u := deref(t).Underlying().(type) {
+	case *types.Slice:
+		return u.Elem()
+	case *types.Array:
+		return u.Elem()
+	case *types.Map:
+		return u.Elem()
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package lint
+
+import (
+	"github.com/mbict/go-validate"
+	"golang.org/x/tools/go/analysis/analysistest"
+	"strings"
+	"testing"
+)
+
+func TestAnalyzer(t *testing.T) {
+	if err := Analyzer.Flags.Set("allow", "postcode"); err != nil {
+		t.Fatal(err)
+	}
+	defer Analyzer.Flags.Set("allow", "")
+
+	analysistest.Run(t, analysistest.TestData(), Analyzer, "a")
+}
+
+// TestBuiltinValidators checks the described validators are known by the validator
+func TestBuiltinValidators(t *testing.T) {
+	v := validate.NewValidator()
+	for name, spec := range validators {
+		args := make([]string, spec.minParams)
+		for i := range args {
+			args[i] = "1"
+		}
+
+		tag := name
+		if len(args) > 0 {
+			tag += "(" + strings.Join(args, ",") + ")"
+		}
+
+		if err := v.Valid("", tag); err == validate.ErrUnknownTag {
+			t.Errorf("validator %q is not a builtin validator", name)
+		}
+	}
+}
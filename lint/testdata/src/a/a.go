@@ -0,0 +1,42 @@
+package a
+
+import "time"
+
+type ID string
+
+func (id ID) String() string { return string(id) }
+
+type Valid struct {
+	Name     string            `validate:"required;min(3);max(40);regexp(^[a-z]+$)"`
+	Age      uint              `validate:"between(18,99)"`
+	Score    float64           `validate:"around(-1.5,1.5)"`
+	Email    *string           `validate:"not_empty"`
+	ID       ID                `validate:"uuid4"`
+	Tags     []string          `validate:"min(1);enum(a,b);dive;alpha"`
+	Labels   map[string]int    `validate:"keys;alpha;endkeys;dive;min(1)"`
+	Postcode string            `validate:"postcode(NL)"`
+	Start    time.Time         `validate:"required"`
+	End      time.Time         `validate:"gtfield(Start)"`
+	Company  string            `validate:"group(business);required;endgroup"`
+	Value    interface{}       `validate:"email"`
+	Skipped  int               `validate:"-"`
+	Mask     uint              `validate:"min(0x10);max(1_000)"`
+	Other    map[string]string `json:"other"`
+}
+
+type Invalid struct {
+	Syntax  string         `validate:"min(3"`              // want `invalid validate tag syntax "min\(3"`
+	Unknown string         `validate:"required;mx(6)"`     // want `unknown validator "mx"`
+	Count   string         `validate:"between(1)"`         // want `between expects 2 parameters, got 1`
+	Param   string         `validate:"min(abc)"`           // want `min has bad parameter "abc" for string`
+	Length  []int          `validate:"max(1.5)"`           // want `max has bad parameter "1.5" for \[\]int`
+	Minus   uint           `validate:"min(-1)"`            // want `min has bad parameter "-1" for uint`
+	Kind    int            `validate:"email"`              // want `email does not support int`
+	Pointer *int           `validate:"min(1)"`             // want `min does not support \*int`
+	Regexp  string         `validate:"regexp([a-z)"`       // want `regexp has invalid regexp "\[a-z"`
+	NoArgs  string         `validate:"eqfield"`            // want `eqfield expects 1 parameter, got 0`
+	Dive    string         `validate:"dive;required"`      // want `dive used on string, expected a slice, array or map`
+	Keys    []string       `validate:"keys;alpha"`         // want `keys used on \[\]string, expected a map`
+	KeyKind map[int]string `validate:"keys;email;endkeys"` // want `email does not support int`
+	Elem    []int          `validate:"dive;alpha"`         // want `alpha does not support int`
+}
@@ -0,0 +1,89 @@
+package validate
+
+import (
+	"reflect"
+)
+
+// openAPIPrefix is the prefix of the references to the component schemas
+const openAPIPrefix = "#/components/schemas/"
+
+// Components holds the component schemas of an OpenAPI 3.1 document
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas"`
+}
+
+// OpenAPI generates the component schemas of an OpenAPI 3.1 document for the
+// registered structures
+type OpenAPI struct {
+	validator Validator
+	extension string
+	types     []reflect.Type
+}
+
+// OpenAPIOption is an option for the OpenAPI generator
+type OpenAPIOption func(*OpenAPI)
+
+// OpenAPIValidatorOption sets the validator used to compile the structures,
+// the default validator is used when not set
+func OpenAPIValidatorOption(validator Validator) OpenAPIOption {
+	return func(o *OpenAPI) {
+		o.validator = validator
+	}
+}
+
+// OpenAPIExtensionOption lists the validators without a schema keyword, like the
+// validators registered with SetValidationFunc, in the vendor extension with
+// the given name. The name should start with x-.
+func OpenAPIExtensionOption(name string) OpenAPIOption {
+	return func(o *OpenAPI) {
+		o.extension = name
+	}
+}
+
+// NewOpenAPI creates a new OpenAPI component schema generator
+func NewOpenAPI(options ...OpenAPIOption) *OpenAPI {
+	o := &OpenAPI{
+		validator: defaultValidator,
+	}
+
+	for _, option := range options {
+		option(o)
+	}
+	return o
+}
+
+// Register adds the structures to the generated component schemas
+func (o *OpenAPI) Register(v ...interface{}) {
+	for _, value := range v {
+		o.types = append(o.types, reflect.TypeOf(value))
+	}
+}
+
+// Components generates the component schemas for the registered structures.
+// The schemas are named after the structure types, nested structures are added
+// as components as well and referred to with $ref.
+func (o *OpenAPI) Components() (*Components, error) {
+	mv, ok := o.validator.(*validator)
+	if !ok {
+		return nil, ErrUnsupported
+	}
+
+	e := newSchemaExporter(openAPIPrefix, o.extension)
+	for _, t := range o.types {
+		for t != nil && t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+
+		if t == nil || t.Kind() != reflect.Struct {
+			return nil, ErrUnsupported
+		}
+
+		rules, err := mv.structRulesFor(t, groupSet{})
+		if err != nil {
+			return nil, err
+		}
+		e.structRef(t, rules)
+	}
+
+	return &Components{Schemas: e.defs}, nil
+}
@@ -0,0 +1,104 @@
+package validate_test
+
+import (
+	"encoding/json"
+	"github.com/mbict/go-validate"
+	. "gopkg.in/check.v1"
+)
+
+type OpenAPISuite struct{}
+
+var _ = Suite(&OpenAPISuite{})
+
+type openAPIItem struct {
+	SKU      string `json:"sku" validate:"required;postcode"`
+	Quantity int    `json:"quantity" validate:"min(1)"`
+}
+
+type openAPIOrder struct {
+	ID       string           `json:"id" validate:"uuid4"`
+	Lines    [][]*openAPIItem `json:"lines" validate:"required"`
+	Internal string           `json:"internal" validate:"-"`
+	Customer openAPICustomer  `json:"customer"`
+}
+
+type openAPICustomer struct {
+	Name string `json:"name" validate:"required"`
+}
+
+func (s *OpenAPISuite) TestComponents(c *C) {
+	validator := validate.NewValidator(validate.NameResolverOption(validate.JsonNameResolver))
+	validator.SetValidationFunc("postcode", func(v interface{}, params []string) error {
+		return nil
+	})
+
+	generator := validate.NewOpenAPI(
+		validate.OpenAPIValidatorOption(validator),
+		validate.OpenAPIExtensionOption("x-validate"),
+	)
+	generator.Register(&openAPIOrder{}, openAPICustomer{})
+
+	components, err := generator.Components()
+	c.Assert(err, IsNil)
+
+	data, err := json.Marshal(components)
+	c.Assert(err, IsNil)
+
+	var result, expected interface{}
+	c.Assert(json.Unmarshal(data, &result), IsNil)
+	c.Assert(json.Unmarshal([]byte(`{
+		"schemas": {
+			"openAPIOrder": {
+				"type": "object",
+				"properties": {
+					"id": {"type": "string", "format": "uuid"},
+					"lines": {
+						"type": "array",
+						"minItems": 1,
+						"items": {"type": "array", "items": {"$ref": "#/components/schemas/openAPIItem"}}
+					},
+					"customer": {"$ref": "#/components/schemas/openAPICustomer"}
+				},
+				"required": ["lines"]
+			},
+			"openAPIItem": {
+				"type": "object",
+				"properties": {
+					"sku": {"type": "string", "minLength": 1, "x-validate": ["postcode"]},
+					"quantity": {"type": "integer", "minimum": 1}
+				},
+				"required": ["sku"]
+			},
+			"openAPICustomer": {
+				"type": "object",
+				"properties": {
+					"name": {"type": "string", "minLength": 1}
+				},
+				"required": ["name"]
+			}
+		}
+	}`), &expected), IsNil)
+	c.Assert(result, DeepEquals, expected)
+}
+
+func (s *OpenAPISuite) TestComponentsWithoutExtension(c *C) {
+	validator := validate.NewValidator()
+	validator.SetValidationFunc("postcode", func(v interface{}, params []string) error {
+		return nil
+	})
+
+	generator := validate.NewOpenAPI(validate.OpenAPIValidatorOption(validator))
+	generator.Register(openAPIItem{})
+
+	components, err := generator.Components()
+	c.Assert(err, IsNil)
+	c.Assert(components.Schemas["openAPIItem"].Properties["SKU"].Extensions, IsNil)
+}
+
+func (s *OpenAPISuite) TestComponentsUnsupported(c *C) {
+	generator := validate.NewOpenAPI()
+	generator.Register("order")
+
+	_, err := generator.Components()
+	c.Assert(err, Equals, validate.ErrUnsupported)
+}
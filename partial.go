@@ -0,0 +1,107 @@
+package validate
+
+import (
+	"context"
+	"strings"
+)
+
+// anyElement is the path segment selecting all the elements of a slice, array or map
+const anyElement = "*"
+
+// pathsKey is the context key holding the paths selected for validation
+type pathsKey struct{}
+
+// pathSelection holds the paths selected for validation
+type pathSelection struct {
+	paths   pathTree
+	exclude bool
+}
+
+// pathTree holds the selected paths indexed by the path segments. An empty tree
+// selects everything below the segment.
+type pathTree map[string]pathTree
+
+// WithPaths returns a copy of the context that limits the validation to the
+// fields found by the dotted paths. A path segment can be the Go field name or
+// the name resolved by the NameResolverFunc, elements are selected by their
+// index or map key or with `*` for all the elements.
+func WithPaths(ctx context.Context, paths ...string) context.Context {
+	return context.WithValue(ctx, pathsKey{}, pathSelection{paths: newPathTree(paths)})
+}
+
+// WithoutPaths returns a copy of the context that excludes the fields found by
+// the dotted paths from the validation.
+func WithoutPaths(ctx context.Context, paths ...string) context.Context {
+	return context.WithValue(ctx, pathsKey{}, pathSelection{paths: newPathTree(paths), exclude: true})
+}
+
+// pathsFromContext returns the paths selected for validation stored in the context
+func pathsFromContext(ctx context.Context) pathSelection {
+	selection, _ := ctx.Value(pathsKey{}).(pathSelection)
+	return selection
+}
+
+// newPathTree creates a tree out of the dotted paths
+func newPathTree(paths []string) pathTree {
+	tree := pathTree{}
+next:
+	for _, path := range paths {
+		node := tree
+		for _, segment := range strings.Split(path, ".") {
+			child, ok := node[segment]
+			if !ok {
+				child = pathTree{}
+				node[segment] = child
+			} else if len(child) == 0 {
+				// a shorter or the same path already selects everything below
+				continue next
+			}
+			node = child
+		}
+
+		// a path selects everything below, longer paths are no longer needed
+		for segment := range node {
+			delete(node, segment)
+		}
+	}
+	return tree
+}
+
+// selectPath moves the path selection to the first segment found with one of
+// the names and reports whether the value at the segment is validated. The
+// caller restores the previous selection when done with the value.
+func (vs *validation) selectPath(names ...string) bool {
+	if vs.paths == nil {
+		return true
+	}
+
+	var sub pathTree
+	found := false
+	for _, name := range names {
+		if sub, found = vs.paths[name]; found {
+			break
+		}
+	}
+
+	switch {
+	case !found && vs.exclude:
+		vs.paths = nil
+		return true
+	case !found:
+		return false
+	case len(sub) == 0 && vs.exclude:
+		return false
+	case len(sub) == 0:
+		vs.paths = nil
+		return true
+	}
+
+	vs.paths = sub
+	return true
+}
+
+// validatesSelf reports whether the validators of the value itself are used,
+// they are skipped for values only selected to reach a nested path.
+func (vs *validation) validatesSelf() bool {
+	return vs.paths == nil || vs.exclude
+}
@@ -0,0 +1,157 @@
+package validate
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// CachedStruct describes the compiled rules of a structure found in the cache
+type CachedStruct struct {
+	Type   reflect.Type  // type of the structure
+	Groups []string      // active groups the rules are compiled for
+	Fields []CachedField // rules of the fields and of their elements
+}
+
+// CachedField describes the compiled rules of a field, or of the elements of a
+// slice, array or map field
+type CachedField struct {
+	Name     string       // path using the resolved names as used in Errors, elements are selected with `*`
+	Path     string       // path using the Go field names, elements are selected with `*`
+	Rules    []CachedRule // validators of the value
+	KeyRules []CachedRule // validators of the map keys
+	Struct   reflect.Type // nested structure validated with its own rules, nil when none
+}
+
+// CachedRule describes a compiled validator
+type CachedRule struct {
+	Name   string   // name of the validator (e.g. `min`)
+	Params []string // parameters of the validator (e.g. `3`)
+}
+
+// Precompile compiles the rules of the structures with the default validator, see Validator.Precompile
+func Precompile(v ...interface{}) error {
+	return defaultValidator.Precompile(v...)
+}
+
+// CachedRules lists the compiled rules of the default validator, see Validator.CachedRules
+func CachedRules() []CachedStruct {
+	return defaultValidator.CachedRules()
+}
+
+// Precompile compiles and caches the rules of the structures, including the
+// nested structures, so the first validation does not pay for the compilation.
+// The values can be structures or pointers to structures, nil pointers of a
+// structure type are accepted. The errors are returned indexed per type name
+// qualified with the package path, e.g. `github.com/acme/api.User`.
+func (mv *validator) Precompile(v ...interface{}) error {
+	return mv.PrecompileCtx(context.Background(), v...)
+}
+
+// PrecompileCtx compiles the rules of the structures like Precompile for the
+// validation groups active in the context.
+func (mv *validator) PrecompileCtx(ctx context.Context, v ...interface{}) error {
+	groups := groupsFromContext(ctx)
+
+	var errs Errors
+	for _, value := range v {
+		t := reflect.TypeOf(value)
+		if t == nil {
+			errs.Add("<nil>", ErrUnsupported)
+			continue
+		}
+
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+
+		if t.Kind() != reflect.Struct {
+			errs.Add(qualifiedTypeName(t), ErrUnsupported)
+			continue
+		}
+
+		if _, err := mv.structRulesFor(t, groups); err != nil {
+			errs.Add(qualifiedTypeName(t), err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// qualifiedTypeName returns the name of the type qualified with the package path,
+// unnamed and predeclared types are named by their description
+func qualifiedTypeName(t reflect.Type) string {
+	if t.Name() == "" || t.PkgPath() == "" {
+		return t.String()
+	}
+	return t.PkgPath() + "." + t.Name()
+}
+
+// CachedRules lists the structures found in the rules cache with their compiled
+// rules, ordered by type name and groups. Changing the configuration empties
+// the cache.
+func (mv *validator) CachedRules() []CachedStruct {
+	var cached []CachedStruct
+	mv.ruleCache().rules.Range(func(key, value interface{}) bool {
+		k := key.(ruleKey)
+		cs := CachedStruct{Type: k.typ}
+		if k.groups != "" {
+			cs.Groups = strings.Split(k.groups, ",")
+		}
+
+		for _, r := range *value.(*rules) {
+			cs.Fields = r.describe(r.Name, r.Field.Name, r.Field.Type, cs.Fields)
+		}
+		cached = append(cached, cs)
+		return true
+	})
+
+	sort.Slice(cached, func(i, j int) bool {
+		if ti, tj := qualifiedTypeName(cached[i].Type), qualifiedTypeName(cached[j].Type); ti != tj {
+			return ti < tj
+		}
+		return strings.Join(cached[i].Groups, ",") < strings.Join(cached[j].Groups, ",")
+	})
+	return cached
+}
+
+// describe appends the description of the rule for a value of the given type
+// and of the rules of its elements to the fields
+func (r *rule) describe(name, path string, t reflect.Type, fields []CachedField) []CachedField {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	field := CachedField{
+		Name:     name,
+		Path:     path,
+		Rules:    describeValidators(r.Validators),
+		KeyRules: describeValidators(r.KeyValidators),
+	}
+	if r.IsStruct {
+		field.Struct = t
+	}
+	fields = append(fields, field)
+
+	if r.Elem != nil {
+		fields = r.Elem.describe(name+"."+anyElement, path+"."+anyElement, t.Elem(), fields)
+	}
+	return fields
+}
+
+// describeValidators returns the names and copies of the parameters of the validators
+func describeValidators(validators []validatorTag) []CachedRule {
+	if len(validators) == 0 {
+		return nil
+	}
+
+	described := make([]CachedRule, len(validators))
+	for i, v := range validators {
+		described[i] = CachedRule{Name: v.Name, Params: append([]string(nil), v.Args...)}
+	}
+	return described
+}
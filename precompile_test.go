@@ -0,0 +1,97 @@
+package validate_test
+
+import (
+	"context"
+	"github.com/mbict/go-validate"
+	. "gopkg.in/check.v1"
+	"reflect"
+)
+
+type PrecompileSuite struct{}
+
+var _ = Suite(&PrecompileSuite{})
+
+type precompileAddress struct {
+	Street string `validate:"required;max(40)"`
+}
+
+type precompileUser struct {
+	Name      string                       `validate:"required;min(3)" json:"name"`
+	Emails    []string                     `validate:"min(1);dive;email"`
+	Labels    map[string]int               `validate:"keys;alpha;endkeys"`
+	Address   *precompileAddress           `validate:"required"`
+	Addresses map[string]precompileAddress `validate:"group(full);max(3)"`
+	Skipped   string                       `validate:"-"`
+}
+
+type precompileBroken struct {
+	Name string `validate:"unknown"`
+}
+
+func (s *PrecompileSuite) TestPrecompile(c *C) {
+	v := validate.NewValidator()
+	c.Assert(v.Precompile(precompileUser{}, (*precompileAddress)(nil)), IsNil)
+
+	cached := v.CachedRules()
+	c.Assert(cached, HasLen, 2)
+	c.Assert(cached[0].Type, Equals, reflect.TypeOf(precompileAddress{}))
+	c.Assert(cached[1].Type, Equals, reflect.TypeOf(precompileUser{}))
+	c.Assert(cached[1].Groups, IsNil)
+}
+
+func (s *PrecompileSuite) TestPrecompileErrors(c *C) {
+	v := validate.NewValidator()
+	err := v.Precompile(precompileBroken{}, precompileUser{}, 1, nil)
+
+	c.Assert(err, DeepEquals, validate.Errors{
+		"github.com/mbict/go-validate_test.precompileBroken": {validate.ErrUnknownTag},
+		"int":   {validate.ErrUnsupported},
+		"<nil>": {validate.ErrUnsupported},
+	})
+
+	// the valid structures are compiled
+	c.Assert(v.CachedRules(), HasLen, 2)
+}
+
+func (s *PrecompileSuite) TestCachedRulesParamsAreCopies(c *C) {
+	type test struct {
+		Role string `validate:"in(admin,user)"`
+	}
+
+	v := validate.NewValidator()
+	c.Assert(v.Precompile(test{}), IsNil)
+
+	v.CachedRules()[0].Fields[0].Rules[0].Params[0] = "changed"
+	c.Assert(v.Validate(test{Role: "admin"}), IsNil)
+}
+
+func (s *PrecompileSuite) TestPrecompileGroups(c *C) {
+	v := validate.NewValidator()
+	c.Assert(v.PrecompileCtx(validate.WithGroups(context.Background(), "full", "admin"), precompileAddress{}), IsNil)
+
+	cached := v.CachedRules()
+	c.Assert(cached, HasLen, 1)
+	c.Assert(cached[0].Groups, DeepEquals, []string{"admin", "full"})
+}
+
+func (s *PrecompileSuite) TestCachedRules(c *C) {
+	v := validate.NewValidator()
+	v.SetNameResolver(validate.JsonNameResolver)
+	c.Assert(v.Precompile(precompileUser{}), IsNil)
+
+	cached := v.CachedRules()
+	c.Assert(cached, HasLen, 2)
+	c.Assert(cached[1].Fields, DeepEquals, []validate.CachedField{
+		{Name: "name", Path: "Name", Rules: []validate.CachedRule{{Name: "required"}, {Name: "min", Params: []string{"3"}}}},
+		{Name: "Emails", Path: "Emails", Rules: []validate.CachedRule{{Name: "min", Params: []string{"1"}}}},
+		{Name: "Emails.*", Path: "Emails.*", Rules: []validate.CachedRule{{Name: "email"}}},
+		{Name: "Labels", Path: "Labels", KeyRules: []validate.CachedRule{{Name: "alpha"}}},
+		{Name: "Address", Path: "Address", Rules: []validate.CachedRule{{Name: "required"}}, Struct: reflect.TypeOf(precompileAddress{})},
+		{Name: "Addresses", Path: "Addresses"},
+		{Name: "Addresses.*", Path: "Addresses.*", Struct: reflect.TypeOf(precompileAddress{})},
+	})
+
+	// changing the configuration empties the cache
+	c.Assert(v.SetValidationFunc("custom", failing), IsNil)
+	c.Assert(v.CachedRules(), HasLen, 0)
+}
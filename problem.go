@@ -0,0 +1,194 @@
+package validate
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// ProblemContentType is the content type of a problem details document (RFC 7807)
+const ProblemContentType = "application/problem+json"
+
+// Problem is a problem details document (RFC 7807) describing the validation
+// errors as invalid params
+type Problem struct {
+	Type          string         `json:"type,omitempty"`
+	Title         string         `json:"title,omitempty"`
+	Status        int            `json:"status,omitempty"`
+	Detail        string         `json:"detail,omitempty"`
+	Instance      string         `json:"instance,omitempty"`
+	InvalidParams []InvalidParam `json:"invalid-params"`
+
+	translator *Translator // translator rendering the reasons
+	locale     string      // locale of the rendered reasons
+}
+
+// InvalidParam describes a single error of a field in a Problem
+type InvalidParam struct {
+	Name    string `json:"name"`           // name of the field as used in Errors
+	Reason  string `json:"reason"`         // message of the error
+	Code    string `json:"code,omitempty"` // code of the validator that failed
+	Pointer string `json:"pointer"`        // JSON pointer (RFC 6901) to the field
+}
+
+type ProblemOption func(*Problem)
+
+// ProblemTypeOption sets the URI reference identifying the problem type
+func ProblemTypeOption(uri string) ProblemOption {
+	return func(p *Problem) {
+		p.Type = uri
+	}
+}
+
+// ProblemTitleOption sets the short summary of the problem
+func ProblemTitleOption(title string) ProblemOption {
+	return func(p *Problem) {
+		p.Title = title
+	}
+}
+
+// ProblemStatusOption sets the HTTP status code of the problem
+func ProblemStatusOption(status int) ProblemOption {
+	return func(p *Problem) {
+		p.Status = status
+	}
+}
+
+// ProblemDetailOption sets the explanation specific to this occurrence of the problem
+func ProblemDetailOption(detail string) ProblemOption {
+	return func(p *Problem) {
+		p.Detail = detail
+	}
+}
+
+// ProblemInstanceOption sets the URI reference identifying this occurrence of the problem
+func ProblemInstanceOption(uri string) ProblemOption {
+	return func(p *Problem) {
+		p.Instance = uri
+	}
+}
+
+// ProblemTranslatorOption renders the reasons with the translator in the locale
+func ProblemTranslatorOption(translator *Translator, locale string) ProblemOption {
+	return func(p *Problem) {
+		p.translator = translator
+		p.locale = locale
+	}
+}
+
+// NewProblem creates a problem details document out of the errors. The problem
+// has the status 422 Unprocessable Entity unless set otherwise by the options.
+func NewProblem(errs Errors, options ...ProblemOption) *Problem {
+	p := &Problem{
+		Type:   "about:blank",
+		Title:  "Validation failed",
+		Status: http.StatusUnprocessableEntity,
+	}
+
+	for _, option := range options {
+		option(p)
+	}
+
+	names := make([]string, 0, len(errs))
+	for name := range errs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	p.InvalidParams = make([]InvalidParam, 0, len(errs))
+	for _, name := range names {
+		for _, err := range errs[name] {
+			p.InvalidParams = append(p.InvalidParams, p.invalidParam(name, err))
+		}
+	}
+	return p
+}
+
+// invalidParam creates the invalid param for the error of the field
+func (p *Problem) invalidParam(name string, err error) InvalidParam {
+	param := InvalidParam{
+		Name:    name,
+		Reason:  err.Error(),
+		Pointer: jsonPointer(name),
+	}
+
+	if fe, ok := err.(*FieldError); ok {
+		param.Code = fe.Code
+	}
+
+	if p.translator != nil {
+		param.Reason = p.translator.Translate(p.locale, err)
+	}
+	return param
+}
+
+// Errors restores the errors described by the invalid params
+func (p *Problem) Errors() Errors {
+	var errs Errors
+	for _, param := range p.InvalidParams {
+		err := error(&validationError{template: param.Reason})
+		if param.Code != "" {
+			err = &FieldError{
+				Code: param.Code,
+				Name: param.Name,
+				Err:  err,
+			}
+		}
+		errs.Add(param.Name, err)
+	}
+	return errs
+}
+
+// Write writes the problem as the response with the status of the problem, a
+// problem without a status is written as 422 Unprocessable Entity
+func (p *Problem) Write(w http.ResponseWriter) error {
+	problem := *p
+	if problem.Status == 0 {
+		problem.Status = http.StatusUnprocessableEntity
+	}
+
+	data, err := json.Marshal(&problem)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(problem.Status)
+	_, err = w.Write(data)
+	return err
+}
+
+// ParseProblem parses a problem details document
+func ParseProblem(data []byte) (*Problem, error) {
+	p := &Problem{}
+	if err := json.Unmarshal(data, p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// pointerEscaper escapes the reference tokens of a JSON pointer
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// jsonPointer converts the dotted name of a field into a JSON pointer. Errors
+// of the structure itself, reported under `_`, point to the structure. Errors
+// of a map key, reported under the key in brackets, point to the map entry.
+func jsonPointer(name string) string {
+	var key []string
+	if i := strings.IndexByte(name, '['); i > 0 && strings.HasSuffix(name, "]") {
+		name, key = name[:i], []string{name[i+1 : len(name)-1]}
+	}
+
+	segments := append(strings.Split(name, "."), key...)
+	if segments[len(segments)-1] == "_" {
+		segments = segments[:len(segments)-1]
+	}
+
+	var pointer strings.Builder
+	for _, segment := range segments {
+		pointer.WriteByte('/')
+		pointer.WriteString(pointerEscaper.Replace(segment))
+	}
+	return pointer.String()
+}
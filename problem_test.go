@@ -0,0 +1,93 @@
+package validate_test
+
+import (
+	"encoding/json"
+	"github.com/mbict/go-validate"
+	. "gopkg.in/check.v1"
+	"net/http/httptest"
+)
+
+type ProblemSuite struct{}
+
+var _ = Suite(&ProblemSuite{})
+
+func (s *ProblemSuite) TestNewProblem(c *C) {
+	test := struct {
+		Name      string            `json:"name" validate:"required"`
+		Addresses map[string]string `json:"addresses" validate:"dive;min(3)"`
+		Labels    map[string]string `json:"labels" validate:"keys;min(4);endkeys"`
+	}{
+		Addresses: map[string]string{"a/b": "x"},
+		Labels:    map[string]string{"a.b": "x"},
+	}
+
+	validator := validate.NewValidator(validate.NameResolverOption(validate.JsonNameResolver))
+	err := validator.ValidateAll(test)
+	c.Assert(err, NotNil)
+
+	errs := err.(validate.Errors)
+	errs.Add("_", validate.ErrInvalid)
+
+	problem := validate.NewProblem(errs,
+		validate.ProblemTitleOption("Invalid address"),
+		validate.ProblemInstanceOption("/users/1"),
+	)
+
+	data, err := json.Marshal(problem)
+	c.Assert(err, IsNil)
+	c.Assert(string(data), Equals, `{"type":"about:blank","title":"Invalid address","status":422,"instance":"/users/1","invalid-params":[`+
+		`{"name":"_","reason":"invalid value","pointer":""},`+
+		`{"name":"addresses.a/b","reason":"less than min","code":"min","pointer":"/addresses/a~1b"},`+
+		`{"name":"labels[a.b]","reason":"less than min","code":"min","pointer":"/labels/a.b"},`+
+		`{"name":"name","reason":"required","code":"required","pointer":"/name"}]}`)
+
+	problem = validate.NewProblem(errs, validate.ProblemTranslatorOption(validator.Translator(), "nl"))
+	c.Assert(problem.InvalidParams[3].Reason, Equals, "name is verplicht")
+}
+
+func (s *ProblemSuite) TestParseProblem(c *C) {
+	var errs validate.Errors
+	errs.Add("name", &validate.FieldError{Code: "required", Name: "name", Err: validate.ErrRequired})
+	errs.Add("age", validate.ErrMin)
+
+	data, err := json.Marshal(validate.NewProblem(errs))
+	c.Assert(err, IsNil)
+
+	problem, err := validate.ParseProblem(data)
+	c.Assert(err, IsNil)
+	c.Assert(problem.Status, Equals, 422)
+
+	restored := problem.Errors()
+	c.Assert(restored, HasLen, 2)
+	c.Assert(restored.Error(), Equals, errs.Error())
+
+	fieldErr, ok := restored["name"][0].(*validate.FieldError)
+	c.Assert(ok, Equals, true)
+	c.Assert(fieldErr.Code, Equals, "required")
+
+	_, err = validate.ParseProblem([]byte(`{`))
+	c.Assert(err, NotNil)
+}
+
+func (s *ProblemSuite) TestWriteProblem(c *C) {
+	var errs validate.Errors
+	errs.Add("name", validate.ErrRequired)
+
+	rec := httptest.NewRecorder()
+	err := validate.NewProblem(errs, validate.ProblemStatusOption(400)).Write(rec)
+	c.Assert(err, IsNil)
+	c.Assert(rec.Code, Equals, 400)
+	c.Assert(rec.Header().Get("Content-Type"), Equals, validate.ProblemContentType)
+	c.Assert(rec.Body.String(), Equals, `{"type":"about:blank","title":"Validation failed","status":400,"invalid-params":[{"name":"name","reason":"required","pointer":"/name"}]}`)
+
+	// a problem without a status is written as 422
+	rec = httptest.NewRecorder()
+	err = validate.NewProblem(errs, validate.ProblemStatusOption(0)).Write(rec)
+	c.Assert(err, IsNil)
+	c.Assert(rec.Code, Equals, 422)
+	c.Assert(rec.Body.String(), Equals, `{"type":"about:blank","title":"Validation failed","status":422,"invalid-params":[{"name":"name","reason":"required","pointer":"/name"}]}`)
+
+	rec = httptest.NewRecorder()
+	c.Assert((&validate.Problem{}).Write(rec), IsNil)
+	c.Assert(rec.Code, Equals, 422)
+}
@@ -1,36 +1,126 @@
 package validate
 
 import (
+	"context"
 	"fmt"
 	"reflect"
+	"sort"
+	"strconv"
 )
 
 type ValidateFunc func(i interface{}) error
 
-type structRules map[reflect.Type]rules
+// ruleKey identifies the compiled rules of a structure for a set of active groups
+type ruleKey struct {
+	typ    reflect.Type
+	groups string
+}
 
 type rules []rule
 
 type rule struct {
-	Name       string
-	FieldIndex int
-	IsSlice    bool
-	IsStruct   bool
-	Validators []validatorTag
-	Subset     rules
+	Name          string
+	FieldIndex    int
+	Field         reflect.StructField
+	Tag           string
+	IsSlice       bool
+	IsMap         bool
+	IsStruct      bool
+	Validators    []validatorTag
+	KeyValidators []validatorTag
+	Elem          *rule
+	Subset        *rules
+}
+
+// validation holds the state of a single validation run
+type validation struct {
+	ctx         context.Context
+	stopOnError bool
+	parent      reflect.Value  // structure holding the fields being validated
+	field       *rule          // rule of the field being validated
+	prefix      string         // path of the parent structure
+	goPrefix    string         // path of the parent structure using the Go field names
+	visited     map[visit]bool // structures being validated on the current path
+	paths       pathTree       // paths selected below the current value, nil selects everything
+	exclude     bool           // paths are excluded from the validation instead of selected
+	err         error          // error of the context when the validation is cancelled
+}
+
+// cancelled reports whether the context of the validation is done
+func (vs *validation) cancelled() bool {
+	if vs.err == nil {
+		vs.err = vs.ctx.Err()
+	}
+	return vs.err != nil
+}
+
+// visit identifies a structure that is reached through a pointer
+type visit struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+// enter marks the structure behind the pointer as being validated and reports
+// false when the structure is already being validated higher up the path
+func (vs *validation) enter(v visit) bool {
+	if vs.visited[v] {
+		return false
+	}
+	if vs.visited == nil {
+		vs.visited = make(map[visit]bool)
+	}
+	vs.visited[v] = true
+	return true
+}
+
+// leave marks the structure as done
+func (vs *validation) leave(v visit) {
+	delete(vs.visited, v)
 }
 
-func (r *rules) Validate(value reflect.Value, stopOnError bool) Errors {
+func (r *rules) Validate(value reflect.Value, vs *validation) Errors {
+	parent, field := vs.parent, vs.field
+	vs.parent = value
+	defer func() {
+		vs.parent, vs.field = parent, field
+	}()
+
 	var errs Errors
-	for _, rule := range *r {
+	for i := range *r {
+		if vs.cancelled() {
+			return nil
+		}
+
+		rule := &(*r)[i]
+		paths := vs.paths
+		if !vs.selectPath(rule.Field.Name, rule.Name) {
+			continue
+		}
+
+		vs.field = rule
 		v := value.Field(rule.FieldIndex)
-		if verr := rule.Validate(v, stopOnError); verr != nil {
+		if verr := rule.Validate(v, vs); verr != nil {
 			errs.Merge(verr)
 		}
+		vs.paths = paths
 	}
 
-	// implemented the ValidateInterface
-	if validateFunc, ok := value.Interface().(ValidateInterface); ok {
+	if vs.cancelled() {
+		return nil
+	}
+
+	// the structure is only validated as a whole when not partially selected
+	if !vs.validatesSelf() {
+		return errs
+	}
+
+	// implemented the ValidateCtxInterface or ValidateInterface
+	switch validateFunc := value.Interface().(type) {
+	case ValidateCtxInterface:
+		if err := validateFunc.ValidateCtx(vs.ctx); err != nil {
+			errs.Merge(err)
+		}
+	case ValidateInterface:
 		if err := validateFunc.Validate(); err != nil {
 			errs.Merge(err)
 		}
@@ -42,36 +132,83 @@ func (r *rules) Validate(value reflect.Value, stopOnError bool) Errors {
 	return errs
 }
 
-func (r *rule) Validate(value reflect.Value, stopOnError bool) Errors {
+func (r *rule) Validate(value reflect.Value, vs *validation) Errors {
+	return r.validate(value, r.Name, r.Field.Name, vs)
+}
+
+// validate validates the value and reports the errors found under the given
+// name, elements are reported by their index or map key appended to the name.
+// The goName is the name of the value using the Go field names.
+func (r *rule) validate(value reflect.Value, name, goName string, vs *validation) Errors {
 	var errs Errors
 
-	i := value.Interface()
-	for _, validator := range r.Validators {
-		if err := validator.Fn(i, validator.Args); err != nil {
-			errs.Add(r.Name, err)
+	if vs.validatesSelf() {
+		if verrs := runValidators(r.Validators, value.Interface(), name, goName, vs); verrs != nil {
+			errs.Add(name, verrs...)
 
-			if stopOnError == true {
+			if vs.stopOnError == true {
 				return errs
 			}
 		}
 	}
 
-	if value.Kind() == reflect.Ptr && value.IsNil() {
-		return errs
+	var ptr uintptr
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return errs
+		}
+		ptr = value.Pointer()
+		value = value.Elem()
 	}
 
-	value = reflect.Indirect(value)
-	if r.IsSlice && r.IsStruct {
-		for i := 0; i < value.Len(); i++ {
-			errv := r.Subset.Validate(reflect.Indirect(value.Index(i)), stopOnError)
+	if r.IsSlice && r.Elem != nil {
+		paths := vs.paths
+		for i := 0; i < value.Len() && !vs.cancelled(); i++ {
+			index := strconv.Itoa(i)
+			if !vs.selectPath(index, anyElement) {
+				continue
+			}
+
+			errv := r.Elem.validate(value.Index(i), name+"."+index, goName+"."+index, vs)
 			if errv != nil {
-				errs.MergePrefix(fmt.Sprintf("%s.%d.", r.Name, i), errv)
+				errs.Merge(errv)
 			}
+			vs.paths = paths
+		}
+	} else if r.IsMap && (r.Elem != nil || len(r.KeyValidators) > 0) {
+		paths := vs.paths
+		for _, key := range sortedMapKeys(value) {
+			if vs.cancelled() {
+				break
+			}
+
+			keyString := fmt.Sprint(key.Interface())
+			if !vs.selectPath(keyString, anyElement) {
+				continue
+			}
+
+			if errv := r.validateMapEntry(value, key, keyString, name, goName, vs); errv != nil {
+				errs.Merge(errv)
+			}
+			vs.paths = paths
 		}
 	} else if r.IsStruct {
-		errv := r.Subset.Validate(value, stopOnError)
+		// a structure reached through a pointer that is already being validated
+		// higher up the path is a cycle and is not validated again
+		if ptr != 0 {
+			v := visit{ptr: ptr, typ: value.Type()}
+			if !vs.enter(v) {
+				return errs
+			}
+			defer vs.leave(v)
+		}
+
+		prefix, goPrefix := vs.prefix, vs.goPrefix
+		vs.prefix, vs.goPrefix = prefix+name+".", goPrefix+goName+"."
+		errv := r.Subset.Validate(value, vs)
+		vs.prefix, vs.goPrefix = prefix, goPrefix
 		if errv != nil {
-			errs.MergePrefix(r.Name+".", errv)
+			errs.MergePrefix(name+".", errv)
 		}
 	}
 
@@ -80,3 +217,57 @@ func (r *rule) Validate(value reflect.Value, stopOnError bool) Errors {
 	}
 	return errs
 }
+
+// validateMapEntry validates the key and the element of a map entry. Errors of
+// the key are reported under the key in brackets, e.g. `Labels[foo]`, errors of
+// the element under the key appended to the name, e.g. `Labels.foo`.
+func (r *rule) validateMapEntry(value, key reflect.Value, keyString, name, goName string, vs *validation) Errors {
+	var errs Errors
+	if vs.validatesSelf() {
+		keyName, goKeyName := name+"["+keyString+"]", goName+"["+keyString+"]"
+		if verrs := runValidators(r.KeyValidators, key.Interface(), keyName, goKeyName, vs); verrs != nil {
+			errs.Add(keyName, verrs...)
+
+			if vs.stopOnError == true {
+				return errs
+			}
+		}
+	}
+
+	if r.Elem == nil {
+		return errs
+	}
+
+	if errv := r.Elem.validate(value.MapIndex(key), name+"."+keyString, goName+"."+keyString, vs); errv != nil {
+		errs.Merge(errv)
+	}
+	return errs
+}
+
+// runValidators calls the validators in order against the value. Validation stops
+// when a validator signals the value can be omitted.
+func runValidators(validators []validatorTag, v interface{}, path, goPath string, vs *validation) ErrorList {
+	var errs ErrorList
+	for i := range validators {
+		if err := validators[i].call(v, path, vs); err != nil {
+			if err == errOmitEmpty {
+				return errs
+			}
+			errs = append(errs, vs.fieldError(&validators[i], v, path, goPath, err))
+
+			if vs.stopOnError == true {
+				return errs
+			}
+		}
+	}
+	return errs
+}
+
+// sortedMapKeys returns the keys of a map in a stable order
+func sortedMapKeys(value reflect.Value) []reflect.Value {
+	keys := value.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+	})
+	return keys
+}
@@ -0,0 +1,424 @@
+package validate
+
+import (
+	"encoding/json"
+	"github.com/mbict/go-tags"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SchemaDialect is the JSON Schema dialect of the exported schemas
+const SchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is a JSON Schema (draft 2020-12) describing a value and its validators
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	ContentEncoding      string             `json:"contentEncoding,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	MinLength            *int64             `json:"minLength,omitempty"`
+	MaxLength            *int64             `json:"maxLength,omitempty"`
+	Minimum              json.Number        `json:"minimum,omitempty"`
+	Maximum              json.Number        `json:"maximum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	MinItems             *int64             `json:"minItems,omitempty"`
+	MaxItems             *int64             `json:"maxItems,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	PropertyNames        *Schema            `json:"propertyNames,omitempty"`
+	MinProperties        *int64             `json:"minProperties,omitempty"`
+	MaxProperties        *int64             `json:"maxProperties,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+	Not                  *Schema            `json:"not,omitempty"`
+	Defs                 map[string]*Schema `json:"$defs,omitempty"`
+
+	// Extensions holds the vendor extensions of the schema, the names start with x-
+	Extensions map[string]interface{} `json:"-"`
+}
+
+// schema is used to marshal the keywords of a Schema without its MarshalJSON method
+type schema Schema
+
+// MarshalJSON encodes the schema with the vendor extensions as additional keywords
+func (s *Schema) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal((*schema)(s))
+	if err != nil || len(s.Extensions) == 0 {
+		return data, err
+	}
+
+	extensions, err := json.Marshal(s.Extensions)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) == 2 {
+		return extensions, nil
+	}
+	return append(append(data[:len(data)-1], ','), extensions[1:]...), nil
+}
+
+// schemaFormats maps the validators to the format of a string
+var schemaFormats = map[string]string{
+	"email": "email",
+	"url":   "uri",
+	"uuid":  "uuid",
+	"uuid3": "uuid",
+	"uuid4": "uuid",
+	"uuid5": "uuid",
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// JSONSchema exports the JSON Schema of the structure based on the validators
+// of the default validator
+func JSONSchema(v interface{}) (*Schema, error) {
+	return defaultValidator.JSONSchema(v)
+}
+
+// JSONSchema exports the JSON Schema of the structure based on the validators in
+// the tags. The property names are resolved by the NameResolverFunc, nested
+// structures are defined in $defs and referred to with $ref.
+func (mv *validator) JSONSchema(v interface{}) (*Schema, error) {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, ErrUnsupported
+	}
+
+	rules, err := mv.structRulesFor(t, groupSet{})
+	if err != nil {
+		return nil, err
+	}
+
+	e := newSchemaExporter("#/$defs/", "")
+	e.refs[t] = "#"
+
+	schema := e.structSchema(rules)
+	schema.Schema = SchemaDialect
+	if len(e.defs) > 0 {
+		schema.Defs = e.defs
+	}
+	return schema, nil
+}
+
+// schemaExporter holds the state of a schema export
+type schemaExporter struct {
+	prefix    string                  // prefix of the references to the definitions
+	extension string                  // vendor extension listing the validators without keywords
+	refs      map[reflect.Type]string // references to the exported structures
+	defs      map[string]*Schema      // definitions of the nested structures
+}
+
+// newSchemaExporter creates an exporter referring to the definitions with the prefix
+func newSchemaExporter(prefix, extension string) *schemaExporter {
+	return &schemaExporter{
+		prefix:    prefix,
+		extension: extension,
+		refs:      map[reflect.Type]string{},
+		defs:      map[string]*Schema{},
+	}
+}
+
+// structSchema returns the object schema for the rules of a structure
+func (e *schemaExporter) structSchema(rs *rules) *Schema {
+	schema := &Schema{
+		Type:       "object",
+		Properties: make(map[string]*Schema, len(*rs)),
+	}
+
+	for i := range *rs {
+		r := &(*rs)[i]
+		property := e.valueSchema(r.Field.Type, r)
+		if hasValidator(r.Validators, "required") {
+			schema.Required = append(schema.Required, r.Name)
+		} else if r.Field.Type.Kind() == reflect.Ptr {
+			// optional pointers are encoded as null when nil
+			property = &Schema{AnyOf: []*Schema{property, {Type: "null"}}}
+		}
+		schema.Properties[r.Name] = property
+	}
+	return schema
+}
+
+// structRef returns the reference to the schema of a nested structure, the
+// structure is added to the definitions the first time it is found
+func (e *schemaExporter) structRef(t reflect.Type, rs *rules) *Schema {
+	if ref, ok := e.refs[t]; ok {
+		return &Schema{Ref: ref}
+	}
+
+	name := t.Name()
+	if _, taken := e.defs[name]; taken || name == "" {
+		name = strings.NewReplacer("/", "_", ".", "_").Replace(t.PkgPath()) + "_" + t.Name()
+	}
+
+	ref := e.prefix + name
+	e.refs[t] = ref
+	e.defs[name] = &Schema{}
+	*e.defs[name] = *e.structSchema(rs)
+	return &Schema{Ref: ref}
+}
+
+// valueSchema returns the schema of a value of the type with the validators of the rule
+func (e *schemaExporter) valueSchema(t reflect.Type, r *rule) *Schema {
+	if r == nil {
+		r = &rule{}
+	}
+
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	schema := &Schema{}
+	switch t.Kind() {
+	case reflect.String:
+		schema.Type = "string"
+	case reflect.Bool:
+		schema.Type = "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		schema.Type = "integer"
+	case reflect.Float32, reflect.Float64:
+		schema.Type = "number"
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 && t.Kind() == reflect.Slice {
+			// encoding/json encodes byte slices as base64 strings
+			schema.Type = "string"
+			schema.ContentEncoding = "base64"
+			break
+		}
+		schema.Type = "array"
+		schema.Items = e.valueSchema(t.Elem(), r.Elem)
+		if t.Kind() == reflect.Array {
+			n := int64(t.Len())
+			schema.MinItems, schema.MaxItems = &n, &n
+		}
+	case reflect.Map:
+		schema.Type = "object"
+		schema.AdditionalProperties = e.valueSchema(t.Elem(), r.Elem)
+		if len(r.KeyValidators) > 0 {
+			schema.PropertyNames = &Schema{Type: "string"}
+			e.applyValidators(schema.PropertyNames, r.KeyValidators)
+		}
+	case reflect.Struct:
+		if t == timeType {
+			schema.Type = "string"
+			schema.Format = "date-time"
+			break
+		}
+
+		if r.Subset == nil {
+			schema.Type = "object"
+			break
+		}
+
+		if len(r.Validators) == 0 {
+			return e.structRef(t, r.Subset)
+		}
+
+		// validators are combined with the reference
+		schema.AnyOf = []*Schema{e.structRef(t, r.Subset)}
+	}
+
+	e.applyValidators(schema, r.Validators)
+	return schema
+}
+
+// applyValidators maps the validators to the keywords of the schema, the
+// validators without a keyword are listed in the vendor extension when set
+func (e *schemaExporter) applyValidators(schema *Schema, validators []validatorTag) {
+	for _, v := range validators {
+		args := v.Args
+		switch v.Name {
+		case "required", "not_empty":
+			// empty strings, arrays and objects are not valid, zero numbers
+			// cannot be expressed without excluding the negative numbers
+			schema.setNonEmpty()
+		case "len":
+			if len(args) == 1 {
+				schema.setMin(args[0])
+				schema.setMax(args[0])
+			}
+		case "min":
+			if len(args) == 1 {
+				schema.setMin(args[0])
+			}
+		case "max":
+			if len(args) == 1 {
+				schema.setMax(args[0])
+			}
+		case "between":
+			if len(args) == 2 {
+				lo, hi := orderedParams(args[0], args[1])
+				schema.setMin(lo)
+				schema.setMax(hi)
+			}
+		case "around":
+			if len(args) == 2 {
+				lo, hi := orderedParams(args[0], args[1])
+				below, above := &Schema{}, &Schema{}
+				below.Type, above.Type = schema.Type, schema.Type
+				below.setMax(lo)
+				above.setMin(hi)
+				schema.AnyOf = append(schema.AnyOf, below, above)
+			}
+		case "regexp":
+			if len(args) == 1 {
+				schema.Pattern = args[0]
+			}
+		case "in", "enum":
+			schema.Enum = enumValues(schema.Type, args)
+		case "exclude":
+			schema.Not = &Schema{Enum: enumValues(schema.Type, args)}
+		case "base64":
+			schema.ContentEncoding = "base64"
+		case "omitempty":
+		default:
+			if format, ok := schemaFormats[v.Name]; ok {
+				schema.Format = format
+			} else if e.extension != "" {
+				schema.addExtension(e.extension, v.Param)
+			}
+		}
+	}
+}
+
+// addExtension adds the validator to the list of validators in the vendor extension
+func (s *Schema) addExtension(name string, p tags.Param) {
+	validator := p.Name
+	if len(p.Args) > 0 {
+		validator += "(" + strings.Join(p.Args, ",") + ")"
+	}
+
+	if s.Extensions == nil {
+		s.Extensions = make(map[string]interface{})
+	}
+	list, _ := s.Extensions[name].([]string)
+	s.Extensions[name] = append(list, validator)
+}
+
+// setMin sets the lower bound keyword for the type of the schema
+func (s *Schema) setMin(param string) {
+	switch s.Type {
+	case "string":
+		s.MinLength = lengthParam(param, s.MinLength)
+	case "array":
+		s.MinItems = lengthParam(param, s.MinItems)
+	case "object":
+		s.MinProperties = lengthParam(param, s.MinProperties)
+	case "integer", "number":
+		if n, ok := numberParamValue(param); ok {
+			s.Minimum = n
+		}
+	}
+}
+
+// setNonEmpty sets the lower bound of a string, array or object to 1 unless a
+// larger bound is set already
+func (s *Schema) setNonEmpty() {
+	var bound **int64
+	switch s.Type {
+	case "string":
+		bound = &s.MinLength
+	case "array":
+		bound = &s.MinItems
+	case "object":
+		bound = &s.MinProperties
+	default:
+		return
+	}
+
+	if *bound == nil || **bound < 1 {
+		one := int64(1)
+		*bound = &one
+	}
+}
+
+// setMax sets the upper bound keyword for the type of the schema
+func (s *Schema) setMax(param string) {
+	switch s.Type {
+	case "string":
+		s.MaxLength = lengthParam(param, s.MaxLength)
+	case "array":
+		s.MaxItems = lengthParam(param, s.MaxItems)
+	case "object":
+		s.MaxProperties = lengthParam(param, s.MaxProperties)
+	case "integer", "number":
+		if n, ok := numberParamValue(param); ok {
+			s.Maximum = n
+		}
+	}
+}
+
+// lengthParam returns the length parameter, or the current value when the
+// parameter is not a valid length
+func lengthParam(param string, current *int64) *int64 {
+	n, err := asInt(param)
+	if err != nil || n < 0 {
+		return current
+	}
+	return &n
+}
+
+// numberParamValue returns the parameter as a JSON number
+func numberParamValue(param string) (json.Number, bool) {
+	if i, err := asInt(param); err == nil {
+		return json.Number(strconv.FormatInt(i, 10)), true
+	}
+	if u, err := asUint(param); err == nil {
+		return json.Number(strconv.FormatUint(u, 10)), true
+	}
+	if f, err := asFloat(param); err == nil {
+		return json.Number(strconv.FormatFloat(f, 'g', -1, 64)), true
+	}
+	return "", false
+}
+
+// orderedParams returns the numeric parameters of between and around ordered
+// from low to high
+func orderedParams(a, b string) (string, string) {
+	x, errA := asFloat(a)
+	y, errB := asFloat(b)
+	if errA == nil && errB == nil && x > y {
+		return b, a
+	}
+	return a, b
+}
+
+// enumValues converts the parameters to values of the type of the schema
+func enumValues(schemaType string, params []string) []interface{} {
+	values := make([]interface{}, len(params))
+	for i, param := range params {
+		values[i] = param
+		switch schemaType {
+		case "integer", "number":
+			if n, ok := numberParamValue(param); ok {
+				values[i] = n
+			}
+		case "boolean":
+			if b, err := strconv.ParseBool(param); err == nil {
+				values[i] = b
+			}
+		}
+	}
+	return values
+}
+
+// hasValidator reports whether the validator is found
+func hasValidator(validators []validatorTag, name string) bool {
+	for _, v := range validators {
+		if v.Name == name {
+			return true
+		}
+	}
+	return false
+}
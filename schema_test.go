@@ -0,0 +1,102 @@
+package validate_test
+
+import (
+	"encoding/json"
+	"github.com/mbict/go-validate"
+	. "gopkg.in/check.v1"
+	"time"
+)
+
+type SchemaSuite struct{}
+
+var _ = Suite(&SchemaSuite{})
+
+type schemaAddress struct {
+	City    string `json:"city" validate:"required;max(50)"`
+	Country string `json:"country" validate:"in(NL,BE)"`
+}
+
+type schemaUser struct {
+	Name      string                    `json:"name" validate:"required;between(3,20)"`
+	Email     string                    `json:"email" validate:"email"`
+	Age       int                       `json:"age" validate:"between(99,18)"`
+	Score     float64                   `json:"score" validate:"around(-1.5,1.5)"`
+	Code      string                    `json:"code" validate:"regexp(^[A-Z]+$)"`
+	Tags      []string                  `json:"tags" validate:"max(5);dive;len(3)"`
+	Labels    map[string]int            `json:"labels" validate:"keys;min(2);endkeys;dive;exclude(0)"`
+	Created   time.Time                 `json:"created"`
+	Avatar    []byte                    `json:"avatar"`
+	Address   *schemaAddress            `json:"address"`
+	Addresses map[string]*schemaAddress `json:"addresses"`
+	Friends   []*schemaUser             `json:"friends"`
+}
+
+func (s *SchemaSuite) TestJSONSchema(c *C) {
+	validator := validate.NewValidator(validate.NameResolverOption(validate.JsonNameResolver))
+	schema, err := validator.JSONSchema(&schemaUser{})
+	c.Assert(err, IsNil)
+
+	data, err := json.Marshal(schema)
+	c.Assert(err, IsNil)
+
+	var result, expected interface{}
+	c.Assert(json.Unmarshal(data, &result), IsNil)
+	c.Assert(json.Unmarshal([]byte(`{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"properties": {
+			"name": {"type": "string", "minLength": 3, "maxLength": 20},
+			"email": {"type": "string", "format": "email"},
+			"age": {"type": "integer", "minimum": 18, "maximum": 99},
+			"score": {"type": "number", "anyOf": [{"type": "number", "maximum": -1.5}, {"type": "number", "minimum": 1.5}]},
+			"code": {"type": "string", "pattern": "^[A-Z]+$"},
+			"tags": {"type": "array", "maxItems": 5, "items": {"type": "string", "minLength": 3, "maxLength": 3}},
+			"labels": {
+				"type": "object",
+				"additionalProperties": {"type": "integer", "not": {"enum": [0]}},
+				"propertyNames": {"type": "string", "minLength": 2}
+			},
+			"created": {"type": "string", "format": "date-time"},
+			"avatar": {"type": "string", "contentEncoding": "base64"},
+			"address": {"anyOf": [{"$ref": "#/$defs/schemaAddress"}, {"type": "null"}]},
+			"addresses": {"type": "object", "additionalProperties": {"$ref": "#/$defs/schemaAddress"}},
+			"friends": {"type": "array", "items": {"$ref": "#"}}
+		},
+		"required": ["name"],
+		"$defs": {
+			"schemaAddress": {
+				"type": "object",
+				"properties": {
+					"city": {"type": "string", "minLength": 1, "maxLength": 50},
+					"country": {"type": "string", "enum": ["NL", "BE"]}
+				},
+				"required": ["city"]
+			}
+		}
+	}`), &expected), IsNil)
+	c.Assert(result, DeepEquals, expected)
+}
+
+func (s *SchemaSuite) TestJSONSchemaRequiredKeepsLargerBound(c *C) {
+	schema, err := validate.JSONSchema(struct {
+		Name  string   `validate:"min(3);required"`
+		Code  string   `validate:"required;min(2)"`
+		Tags  []string `validate:"len(2);required"`
+		Notes string   `validate:"required"`
+	}{})
+	c.Assert(err, IsNil)
+	c.Assert(*schema.Properties["Name"].MinLength, Equals, int64(3))
+	c.Assert(*schema.Properties["Code"].MinLength, Equals, int64(2))
+	c.Assert(*schema.Properties["Tags"].MinItems, Equals, int64(2))
+	c.Assert(*schema.Properties["Notes"].MinLength, Equals, int64(1))
+}
+
+func (s *SchemaSuite) TestJSONSchemaUnsupported(c *C) {
+	_, err := validate.JSONSchema("test")
+	c.Assert(err, Equals, validate.ErrUnsupported)
+
+	_, err = validate.JSONSchema(struct {
+		Name string `validate:"unknown"`
+	}{})
+	c.Assert(err, NotNil)
+}
@@ -0,0 +1,203 @@
+package validate
+
+import (
+	"github.com/mbict/go-errors"
+	"github.com/mbict/go-tags"
+	"reflect"
+	"sort"
+	"unicode"
+)
+
+// ErrDiveConflict is the error returned when rules holding a dive or keys marker
+// are appended to a tag that holds one as well, replace the tag instead
+var ErrDiveConflict = errors.New("rules with a dive or keys can not be appended to a tag with a dive or keys")
+
+// FieldRule holds the validators for a field of a structure that are used next
+// to, or instead of, the validators found in the struct tag of the field
+type FieldRule struct {
+	Field   string // Go name of the field
+	Tag     string // validators in the struct tag format, e.g. `required;min(3)`
+	Replace bool   // replace the struct tag instead of appending the validators to it
+}
+
+// fieldRules holds the registered rules of a structure indexed by the Go field name
+type fieldRules map[string]FieldRule
+
+// SetStructRules appends validators to the fields of a structure of the default
+// validator, see Validator.SetStructRules
+func SetStructRules(v interface{}, rules map[string]string) error {
+	return defaultValidator.SetStructRules(v, rules)
+}
+
+// SetFieldRules registers rules for the fields of a structure of the default
+// validator, see Validator.SetFieldRules
+func SetFieldRules(v interface{}, rules ...FieldRule) error {
+	return defaultValidator.SetFieldRules(v, rules...)
+}
+
+// SetStructRules appends the validators to the struct tags of the fields of the
+// structure, the rules map the Go field names to the validators. Use it for
+// structures whose struct tags cannot be changed, like generated or third party
+// types.
+func (mv *validator) SetStructRules(v interface{}, rules map[string]string) error {
+	fields := make([]string, 0, len(rules))
+	for field := range rules {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	fieldRules := make([]FieldRule, len(fields))
+	for i, field := range fields {
+		fieldRules[i] = FieldRule{Field: field, Tag: rules[field]}
+	}
+	return mv.SetFieldRules(v, fieldRules...)
+}
+
+// SetFieldRules registers the rules for the fields of the structure. The
+// validators of a rule are added to the validators of the field in the struct
+// tag, before a dive or keys marker so they apply to the field and not to the
+// elements or keys. Rules holding a dive or keys marker can only be added to a
+// tag without one, ErrDiveConflict is returned otherwise. A rule with Replace
+// set is used instead of the struct tag, a `-` tag leaves the field out. Rules
+// registered later for the same field are added to the earlier ones unless they
+// replace them.
+func (mv *validator) SetFieldRules(v interface{}, rules ...FieldRule) error {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == nil || t.Kind() != reflect.Struct {
+		return ErrUnsupported
+	}
+
+	cfg := mv.config()
+	for _, rule := range rules {
+		sf, ok := t.FieldByName(rule.Field)
+		if !ok || len(sf.Index) != 1 || !unicode.IsUpper(rune(sf.Name[0])) {
+			return errors.New("unknown field " + rule.Field)
+		}
+
+		if rule.Tag != "-" {
+			if _, err := cfg.parseFieldTags(rule.Tag, groupSet{}); err != nil {
+				return err
+			}
+		}
+	}
+
+	// the cached rules of every structure can embed the changed structure, the
+	// cache is replaced together with the configuration
+	return mv.tryConfigure(func(cfg *config) error {
+		registered := cfg.fieldRules[t]
+		if registered == nil {
+			registered = make(fieldRules, len(rules))
+			cfg.fieldRules[t] = registered
+		}
+
+		for _, rule := range rules {
+			if current, ok := registered[rule.Field]; ok && !rule.Replace {
+				if current.Tag == "-" {
+					continue
+				}
+
+				tag, err := joinTags(current.Tag, rule.Tag)
+				if err != nil {
+					return err
+				}
+				rule.Tag, rule.Replace = tag, current.Replace
+			}
+			registered[rule.Field] = rule
+
+			sf, _ := t.FieldByName(rule.Field)
+			if _, err := cfg.fieldTag(t, sf); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// fieldTag returns the tag of the field merged with the registered rule
+func (cfg *config) fieldTag(t reflect.Type, sf reflect.StructField) (string, error) {
+	tag := sf.Tag.Get(cfg.tagName)
+
+	rule, ok := cfg.fieldRules[t][sf.Name]
+	if !ok {
+		return tag, nil
+	}
+
+	if rule.Replace || tag == "" {
+		return rule.Tag, nil
+	}
+
+	if tag == "-" {
+		return tag, nil
+	}
+	return joinTags(tag, rule.Tag)
+}
+
+// joinTags adds the validators of the extra tag to the validators of the field
+// in the tag, they are inserted before the first dive or keys marker of the tag.
+// A group scope open at that point is closed around the inserted validators.
+func joinTags(tag, extra string) (string, error) {
+	if tag == "" {
+		return extra, nil
+	}
+	if extra == "" {
+		return tag, nil
+	}
+
+	params, err := tags.Parse(tag)
+	if err != nil {
+		return "", ErrSyntax
+	}
+
+	extraParams, err := tags.Parse(extra)
+	if err != nil {
+		return "", ErrSyntax
+	}
+
+	at, groups := fieldParamsEnd(params)
+	extraEnd, extraGroups := fieldParamsEnd(extraParams)
+	if at == len(params) && groups == nil {
+		return tag + ";" + extra, nil
+	}
+
+	if at < len(params) && extraEnd < len(extraParams) {
+		return "", ErrDiveConflict
+	}
+
+	joined := append([]tags.Param(nil), params[:at]...)
+	if groups != nil {
+		joined = append(joined, tags.Param{Name: endGroupTag})
+	}
+	joined = append(joined, extraParams...)
+	if at < len(params) {
+		if extraGroups != nil {
+			joined = append(joined, tags.Param{Name: endGroupTag})
+		}
+		if groups != nil {
+			joined = append(joined, tags.Param{Name: groupTag, Args: groups})
+		}
+		joined = append(joined, params[at:]...)
+	}
+	return formatTag(joined), nil
+}
+
+// fieldParamsEnd returns the index of the first dive or keys marker, or the
+// number of params when there is none, and the groups of the group scope that
+// is open at that index
+func fieldParamsEnd(params []tags.Param) (int, []string) {
+	var groups []string
+	for i, p := range params {
+		switch p.Name {
+		case diveTag, keysTag:
+			return i, groups
+		case groupTag:
+			groups = p.Args
+		case endGroupTag:
+			groups = nil
+		}
+	}
+	return len(params), groups
+}
@@ -0,0 +1,117 @@
+package validate_test
+
+import (
+	"context"
+	"github.com/mbict/go-validate"
+	. "gopkg.in/check.v1"
+)
+
+type StructRulesSuite struct{}
+
+var _ = Suite(&StructRulesSuite{})
+
+// vendorUser mimics a third party structure whose tags cannot be changed
+type vendorUser struct {
+	Name     string `validate:"min(3)"`
+	Email    string
+	Nickname string `validate:"required"`
+	Internal string `validate:"-"`
+	Tags     []string
+	Labels   []string          `validate:"max(3);dive;min(2)"`
+	Scoped   []string          `validate:"group(admin);max(1);dive;min(2)"`
+	Meta     map[string]string `validate:"keys;min(2);endkeys"`
+}
+
+func (s *StructRulesSuite) TestSetStructRules(c *C) {
+	validator := validate.NewValidator()
+	err := validator.SetStructRules(vendorUser{}, map[string]string{
+		"Name":  "max(5)",
+		"Email": "required;email",
+		"Tags":  "dive;required",
+	})
+	c.Assert(err, IsNil)
+
+	err = validator.ValidateAll(&vendorUser{Name: "ab", Nickname: "x", Tags: []string{""}})
+	c.Assert(err, NotNil)
+
+	errs := err.(validate.Errors)
+	c.Assert(errs, HasLen, 3)
+	c.Assert(errs["Name"], HasError, validate.ErrMin)
+	c.Assert(errs["Email"], HasError, validate.ErrRequired)
+	c.Assert(errs["Tags.0"], HasError, validate.ErrRequired)
+
+	c.Assert(validator.Validate(vendorUser{Name: "abcdef", Email: "a@b.nl", Nickname: "x"}), ErrorMatches, `Name: \[greater than max\]`)
+}
+
+func (s *StructRulesSuite) TestSetFieldRulesReplace(c *C) {
+	validator := validate.NewValidator()
+	c.Assert(validator.Validate(vendorUser{Name: "abc"}), NotNil)
+
+	err := validator.SetFieldRules(&vendorUser{},
+		validate.FieldRule{Field: "Nickname", Tag: "max(3)", Replace: true},
+		validate.FieldRule{Field: "Internal", Tag: "required"},
+	)
+	c.Assert(err, IsNil)
+
+	// the cached rules are replaced
+	c.Assert(validator.Validate(vendorUser{Name: "abc"}), IsNil)
+
+	err = validator.SetFieldRules(vendorUser{}, validate.FieldRule{Field: "Internal", Tag: "required", Replace: true})
+	c.Assert(err, IsNil)
+	c.Assert(validator.Validate(vendorUser{Name: "abc"}), ErrorMatches, `Internal: \[required\]`)
+}
+
+func (s *StructRulesSuite) TestSetFieldRulesBeforeDive(c *C) {
+	validator := validate.NewValidator()
+	err := validator.SetStructRules(vendorUser{}, map[string]string{
+		"Labels": "required",
+		"Scoped": "required",
+		"Meta":   "max(1)",
+	})
+	c.Assert(err, IsNil)
+
+	// the registered rules apply to the field and not to the elements or keys
+	err = validator.ValidateAll(vendorUser{Name: "abc", Nickname: "x", Labels: []string{"ab", "cd"}, Scoped: []string{"ab"}, Meta: map[string]string{"ab": ""}})
+	c.Assert(err, IsNil)
+
+	err = validator.ValidateAll(vendorUser{Name: "abc", Nickname: "x", Meta: map[string]string{"ab": "", "cd": ""}})
+	c.Assert(err, NotNil)
+
+	errs := err.(validate.Errors)
+	c.Assert(errs, HasLen, 3)
+	c.Assert(errs["Labels"], HasError, validate.ErrRequired)
+	c.Assert(errs["Scoped"], HasError, validate.ErrRequired)
+	c.Assert(errs["Meta"], HasError, validate.ErrMax)
+
+	// the group scope of the struct tag is kept for the elements
+	err = validator.ValidateAllCtx(validate.WithGroups(context.Background(), "admin"), vendorUser{Name: "abc", Nickname: "x", Labels: []string{"ab"}, Scoped: []string{"ab", "c"}})
+	c.Assert(err, NotNil)
+
+	errs = err.(validate.Errors)
+	c.Assert(errs, HasLen, 2)
+	c.Assert(errs["Scoped"], HasError, validate.ErrMax)
+	c.Assert(errs["Scoped.1"], HasError, validate.ErrMin)
+}
+
+func (s *StructRulesSuite) TestSetFieldRulesDiveConflict(c *C) {
+	validator := validate.NewValidator()
+	c.Assert(validator.SetStructRules(vendorUser{}, map[string]string{"Labels": "dive;required"}), Equals, validate.ErrDiveConflict)
+	c.Assert(validator.SetStructRules(vendorUser{}, map[string]string{"Tags": "dive;required"}), IsNil)
+	c.Assert(validator.SetStructRules(vendorUser{}, map[string]string{"Tags": "keys;min(2);endkeys"}), Equals, validate.ErrDiveConflict)
+	c.Assert(validator.SetStructRules(vendorUser{}, map[string]string{"Tags": "min(1)"}), IsNil)
+
+	err := validator.ValidateAll(vendorUser{Name: "abc", Nickname: "x", Tags: []string{""}})
+	c.Assert(err, NotNil)
+	c.Assert(err.(validate.Errors)["Tags.0"], HasError, validate.ErrRequired)
+
+	// a replaced tag holding a dive takes no registered rules with a dive either
+	c.Assert(validator.SetFieldRules(vendorUser{}, validate.FieldRule{Field: "Labels", Tag: "dive;min(1)", Replace: true}), IsNil)
+	c.Assert(validator.SetStructRules(vendorUser{}, map[string]string{"Labels": "dive;required"}), Equals, validate.ErrDiveConflict)
+}
+
+func (s *StructRulesSuite) TestSetFieldRulesErrors(c *C) {
+	validator := validate.NewValidator()
+	c.Assert(validator.SetStructRules("test", map[string]string{"Name": "required"}), Equals, validate.ErrUnsupported)
+	c.Assert(validator.SetStructRules(vendorUser{}, map[string]string{"Unknown": "required"}), ErrorMatches, "unknown field Unknown")
+	c.Assert(validator.SetStructRules(vendorUser{}, map[string]string{"Name": "unknown"}), NotNil)
+}
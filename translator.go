@@ -0,0 +1,178 @@
+package validate
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Catalog holds the message templates of a locale indexed by the code of the
+// validator. A template can hold the placeholders {field} for the name of the
+// field, {value} for the offending value, {params} for all the params and {0},
+// {1}, ... for the individual params.
+type Catalog map[string]string
+
+// valueKey is the catalog key holding the name used for {field} when the error
+// is not reported for a named field (e.g. when using Valid)
+const valueKey = "_value"
+
+// sentinelCodes maps the errors returned for a misused validator to the code
+// used to look up their message instead of the code of the validator
+var sentinelCodes = map[error]string{
+	ErrUnsupported:           "unsupported",
+	ErrBadParameter:          "bad_parameter",
+	ErrInvalidParameterCount: "invalid_parameter_count",
+}
+
+// Translator renders validation errors as messages in the requested locale
+type Translator struct {
+	mu       sync.RWMutex
+	fallback string             // locale used when the requested locale has no message
+	catalogs map[string]Catalog // catalogs indexed by locale
+	frozen   bool               // changes to the catalogs are rejected
+}
+
+// NewTranslator creates a Translator that uses the fallback locale for messages
+// not found in the requested locale
+func NewTranslator(fallback string) *Translator {
+	return &Translator{
+		fallback: fallback,
+		catalogs: make(map[string]Catalog),
+	}
+}
+
+// newDefaultTranslator creates a Translator with the English and Dutch catalogs
+// for the builtin validators
+func newDefaultTranslator() *Translator {
+	t := NewTranslator("en")
+	t.AddCatalog("en", EnglishCatalog)
+	t.AddCatalog("nl", DutchCatalog)
+	return t
+}
+
+// clone returns a copy of the translator with copies of the catalogs
+func (t *Translator) clone() *Translator {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	c := NewTranslator(t.fallback)
+	for locale, catalog := range t.catalogs {
+		c.catalogs[locale] = make(Catalog, len(catalog))
+		for code, message := range catalog {
+			c.catalogs[locale][code] = message
+		}
+	}
+	return c
+}
+
+// freeze makes the catalogs immutable, it is called when the validator using
+// the translator is frozen
+func (t *Translator) freeze() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.frozen = true
+}
+
+// AddCatalog adds the messages to the catalog of the locale, existing messages
+// for the same codes are replaced. It panics with ErrFrozen when a frozen
+// validator uses the translator.
+func (t *Translator) AddCatalog(locale string, catalog Catalog) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.frozen {
+		panic(ErrFrozen)
+	}
+
+	c, ok := t.catalogs[locale]
+	if !ok {
+		c = make(Catalog, len(catalog))
+		t.catalogs[locale] = c
+	}
+	for code, template := range catalog {
+		c[code] = template
+	}
+}
+
+// SetMessage sets the message template for the code in the locale
+func (t *Translator) SetMessage(locale, code, template string) {
+	t.AddCatalog(locale, Catalog{code: template})
+}
+
+// Translate renders the error as a message in the locale. Errors that are not
+// a FieldError or have no message in the catalogs are rendered as is.
+func (t *Translator) Translate(locale string, err error) string {
+	fe, ok := err.(*FieldError)
+	if !ok {
+		return err.Error()
+	}
+
+	code, ok := sentinelCodes[fe.Err]
+	if !ok {
+		code = fe.Code
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	template, ok := t.message(locale, code)
+	if !ok {
+		return err.Error()
+	}
+
+	field := fe.Name
+	if field == "" {
+		field, _ = t.message(locale, valueKey)
+	}
+
+	replacements := []string{
+		"{field}", field,
+		"{value}", fmt.Sprint(fe.Value),
+		"{params}", strings.Join(fe.Params, ", "),
+	}
+	for i, param := range fe.Params {
+		replacements = append(replacements, "{"+strconv.Itoa(i)+"}", param)
+	}
+	return strings.NewReplacer(replacements...).Replace(template)
+}
+
+// TranslateErrors renders all the errors found in Errors or an ErrorList as
+// messages in the locale indexed by the field name. Errors of an ErrorList and
+// any other error are indexed by an empty field name.
+func (t *Translator) TranslateErrors(locale string, err error) map[string][]string {
+	messages := make(map[string][]string)
+	switch errs := err.(type) {
+	case nil:
+	case Errors:
+		for field, fieldErrs := range errs {
+			for _, fieldErr := range fieldErrs {
+				messages[field] = append(messages[field], t.Translate(locale, fieldErr))
+			}
+		}
+	case ErrorList:
+		for _, e := range errs {
+			messages[""] = append(messages[""], t.Translate(locale, e))
+		}
+	default:
+		messages[""] = []string{t.Translate(locale, err)}
+	}
+	return messages
+}
+
+// message looks up the template for the code in the locale, the language of the
+// locale and the fallback locale in that order
+func (t *Translator) message(locale, code string) (string, bool) {
+	locales := []string{locale}
+	if i := strings.IndexAny(locale, "-_"); i > 0 {
+		locales = append(locales, locale[:i])
+	}
+	locales = append(locales, t.fallback)
+
+	for _, l := range locales {
+		if template, ok := t.catalogs[l][code]; ok {
+			return template, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,103 @@
+package validate_test
+
+import (
+	"github.com/mbict/go-validate"
+	. "gopkg.in/check.v1"
+)
+
+type TranslatorSuite struct{}
+
+var _ = Suite(&TranslatorSuite{})
+
+func (s *TranslatorSuite) TestTranslateErrors(c *C) {
+	test := struct {
+		Name string `json:"name" validate:"required"`
+		Age  int    `json:"age" validate:"between(18,99)"`
+	}{Age: 10}
+
+	validator := validate.NewValidator(validate.NameResolverOption(validate.JsonNameResolver))
+	err := validator.ValidateAll(test)
+	c.Assert(err, NotNil)
+
+	messages := validator.Translator().TranslateErrors("en", err)
+	c.Assert(messages, DeepEquals, map[string][]string{
+		"name": {"name is required"},
+		"age":  {"age must be between 18 and 99"},
+	})
+
+	messages = validator.Translator().TranslateErrors("nl-NL", err)
+	c.Assert(messages, DeepEquals, map[string][]string{
+		"name": {"name is verplicht"},
+		"age":  {"age moet tussen 18 en 99 liggen"},
+	})
+
+	// unknown locales use the fallback locale
+	messages = validator.Translator().TranslateErrors("de", err)
+	c.Assert(messages["name"], DeepEquals, []string{"name is required"})
+}
+
+func (s *TranslatorSuite) TestTranslateValid(c *C) {
+	err := validate.ValidAll("abc", "in(foo,bar)")
+	c.Assert(err, NotNil)
+
+	c.Assert(validate.TranslateErrors("nl", err), DeepEquals, map[string][]string{
+		"": {"waarde moet een van foo, bar zijn"},
+	})
+
+	err = validate.ValidAll(complex(0, 0), "required")
+	c.Assert(err, NotNil)
+	c.Assert(validate.TranslateErrors("en", err), DeepEquals, map[string][]string{
+		"": {"value has an unsupported type"},
+	})
+}
+
+func (s *TranslatorSuite) TestCustomCatalog(c *C) {
+	translator := validate.NewTranslator("en")
+	translator.AddCatalog("de", validate.Catalog{
+		"min": "{field} muss mindestens {0} sein, nicht {value}",
+	})
+	translator.SetMessage("en", "notfoo", "{field} cannot be foo")
+
+	validator := validate.NewValidator(
+		validate.TranslatorOption(translator),
+		validate.ValidatorOption("notfoo", func(v interface{}, _ []string) error {
+			if v == "foo" {
+				return validate.ErrInvalid
+			}
+			return nil
+		}),
+	)
+
+	test := struct {
+		A int    `validate:"min(3)"`
+		B string `validate:"notfoo"`
+		C string `validate:"required"`
+	}{A: 1, B: "foo"}
+
+	err := validator.ValidateAll(test)
+	c.Assert(err, NotNil)
+
+	messages := validator.Translator().TranslateErrors("de", err)
+	c.Assert(messages, DeepEquals, map[string][]string{
+		"A": {"A muss mindestens 3 sein, nicht 1"},
+		"B": {"B cannot be foo"},
+		"C": {"required"},
+	})
+}
+
+func (s *TranslatorSuite) TestCatalogsCoverBuiltins(c *C) {
+	builtins := []string{
+		"required", "not_empty", "len", "min", "max", "between", "around", "in",
+		"exclude", "regexp", "url", "email", "numeric", "number", "identifier",
+		"alpha_dash", "alpha_dash_dot", "alpha", "alphanumeric", "uuid", "uuid3",
+		"uuid4", "uuid5", "base64", "enum", "eqfield", "nefield", "gtfield",
+		"ltfield", "required_if", "required_with", "required_without", "excluded_if",
+	}
+
+	for _, catalog := range []validate.Catalog{validate.EnglishCatalog, validate.DutchCatalog} {
+		for _, code := range builtins {
+			_, ok := catalog[code]
+			c.Check(ok, Equals, true, Commentf("no message for %s", code))
+		}
+	}
+}
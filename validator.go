@@ -1,10 +1,12 @@
 package validate
 
 import (
+	"context"
 	"github.com/mbict/go-errors"
 	"github.com/mbict/go-tags"
 	"reflect"
 	"sync"
+	"sync/atomic"
 	"unicode"
 )
 
@@ -13,17 +15,63 @@ type Validator interface {
 	SetTag(tag string)
 	WithTag(tag string) Validator
 	SetValidationFunc(name string, vf ValidatorFunc) error
+	SetContextValidationFunc(name string, vf ContextValidatorFunc) error
+	SetValidationCompiler(name string, cf CompilerFunc) error
 	SetNameResolver(resolver NameResolverFunc)
+	SetTranslator(translator *Translator)
+	Translator() *Translator
 	ValidateAll(v interface{}) error
+	ValidateAllCtx(ctx context.Context, v interface{}) error
 	Validate(v interface{}) error
+	ValidateCtx(ctx context.Context, v interface{}) error
+	ValidatePartial(v interface{}, paths ...string) error
+	ValidateExcept(v interface{}, paths ...string) error
 	ValidAll(val interface{}, tags string) error
+	ValidAllCtx(ctx context.Context, val interface{}, tags string) error
 	Valid(val interface{}, tags string) error
+	ValidCtx(ctx context.Context, val interface{}, tags string) error
+	SetStructRules(v interface{}, rules map[string]string) error
+	SetFieldRules(v interface{}, rules ...FieldRule) error
+	ValidateData(data interface{}, schema *DataSchema) error
+	ValidateAllData(data interface{}, schema *DataSchema) error
+	JSONSchema(v interface{}) (*Schema, error)
+	Clone() Validator
+	Freeze()
+	Precompile(v ...interface{}) error
+	PrecompileCtx(ctx context.Context, v ...interface{}) error
+	CachedRules() []CachedStruct
+}
+
+// markers used within a validatorTag. The keys and endkeys markers start and end
+// the section of validators applied to the keys of a map. Validators after the
+// dive marker are applied to every element of a slice, array or map.
+const (
+	keysTag    = "keys"
+	endKeysTag = "endkeys"
+	diveTag    = "dive"
+)
+
+// fieldTags holds the validators of a field, the validators for its map keys
+// and the validators for its elements when the field tag dives into the value
+type fieldTags struct {
+	validators []validatorTag
+	keys       []validatorTag
+	elem       *fieldTags
 }
 
 // validatorTag represents one of the validatorTag items
 type validatorTag struct {
-	tags.Param               // name of the validator and the arguments to send to the validator func
-	Fn         ValidatorFunc // validation function to call
+	tags.Param                      // name of the validator and the arguments to send to the validator func
+	Fn         ValidatorFunc        // validation function to call
+	CtxFn      ContextValidatorFunc // context aware validation function to call
+}
+
+// call runs the validation function for the value found at the path
+func (t *validatorTag) call(v interface{}, path string, vs *validation) error {
+	if t.CtxFn != nil {
+		return t.CtxFn(vs.fieldContext(v, t.Args, path))
+	}
+	return t.Fn(v, t.Args)
 }
 
 // ValidateInterface describes the interface a structure can embed to enable custom validation of the structure
@@ -31,17 +79,26 @@ type ValidateInterface interface {
 	Validate() error
 }
 
+// ValidateCtxInterface describes the interface a structure can embed to enable custom validation of the
+// structure with the context of the validation. It takes precedence over the ValidateInterface.
+type ValidateCtxInterface interface {
+	ValidateCtx(ctx context.Context) error
+}
+
 // ValidatorFunc is a function that receives the value of a
 // field and the parameters used for the respective validation validatorTag.
 type ValidatorFunc func(v interface{}, params []string) error
 
+// CompilerFunc is a function that parses the parameters of a validation validatorTag
+// once when the tags are parsed and returns the ValidatorFunc to run against the
+// values. An error for bad parameters fails the parsing of the tags.
+type CompilerFunc func(params []string) (ValidatorFunc, error)
+
 // validator implements the Validator interface
 type validator struct {
-	tagName         string                   // structure validatorTag name being used (`validate`)
-	validationFuncs map[string]ValidatorFunc // validator functions map indexed by name
-	structRules     structRules              // structure rules cache
-	mu              sync.RWMutex             // rw mutex for structure rules cache
-	nameResolver    NameResolverFunc         // func to extract the name to use for field error
+	mu     sync.Mutex   // serializes the changes to the configuration
+	cache  atomic.Value // *ruleCache holding the configuration and its compiled rules
+	frozen bool         // changes to the configuration are rejected
 }
 
 // Helper validator so users can use the
@@ -56,6 +113,12 @@ func NameResolverOption(resolver NameResolverFunc) Option {
 	}
 }
 
+func TranslatorOption(translator *Translator) Option {
+	return func(v Validator) {
+		v.SetTranslator(translator)
+	}
+}
+
 func TagOption(tag string) Option {
 	return func(v Validator) {
 		v.SetTag(tag)
@@ -68,22 +131,28 @@ func ValidatorOption(name string, validatorFunc ValidatorFunc) Option {
 	}
 }
 
+func CompilerOption(name string, compilerFunc CompilerFunc) Option {
+	return func(v Validator) {
+		v.SetValidationCompiler(name, compilerFunc)
+	}
+}
+
+func ContextValidatorOption(name string, validatorFunc ContextValidatorFunc) Option {
+	return func(v Validator) {
+		v.SetContextValidationFunc(name, validatorFunc)
+	}
+}
+
 // NewValidator creates a new Validator
 func NewValidator(options ...Option) Validator {
-	v := &validator{
+	cfg := &config{
 		tagName: "validate",
 		validationFuncs: map[string]ValidatorFunc{
 			"omitempty":      omitempty,
 			"required":       required,
 			"not_empty":      notEmpty,
-			"len":            length,
-			"min":            min,
-			"max":            max,
-			"between":        between,
-			"around":         around,
 			"in":             include,
 			"exclude":        exclude,
-			"regexp":         regex,
 			"url":            url,
 			"email":          email,
 			"numeric":        numeric,
@@ -100,10 +169,39 @@ func NewValidator(options ...Option) Validator {
 			"base64":         base64,
 			"enum":           enum,
 		},
-		structRules:  make(structRules),
+		compilers: map[string]CompilerFunc{
+			"len":     compileLength,
+			"min":     compileMin,
+			"max":     compileMax,
+			"between": compileBetween,
+			"around":  compileAround,
+			"regexp":  compileRegexp,
+		},
+		paramChecks: map[string]paramCheckFunc{
+			"len":     checkNumberParams,
+			"min":     checkNumberParams,
+			"max":     checkNumberParams,
+			"between": checkNumberParams,
+			"around":  checkNumberParams,
+		},
+		contextValidationFuncs: map[string]ContextValidatorFunc{
+			"eqfield":          eqField,
+			"nefield":          neField,
+			"gtfield":          gtField,
+			"ltfield":          ltField,
+			"required_if":      requiredIf,
+			"required_with":    requiredWith,
+			"required_without": requiredWithout,
+			"excluded_if":      excludedIf,
+		},
+		fieldRules:   make(map[reflect.Type]fieldRules),
 		nameResolver: DefaultNameResolver,
+		translator:   newDefaultTranslator(),
 	}
 
+	v := &validator{}
+	v.cache.Store(newRuleCache(cfg))
+
 	for _, option := range options {
 		option(v)
 	}
@@ -111,6 +209,11 @@ func NewValidator(options ...Option) Validator {
 	return v
 }
 
+// SetNameResolver allows you to change the way field names are resolved for the default validator
+func SetNameResolver(resolver NameResolverFunc) {
+	defaultValidator.SetNameResolver(resolver)
+}
+
 // SetTag allows you to change the validatorTag name used in structs for the default validator
 func SetTag(tag string) {
 	defaultValidator.SetTag(tag)
@@ -130,85 +233,206 @@ func SetValidationFunc(name string, vf ValidatorFunc) error {
 	return defaultValidator.SetValidationFunc(name, vf)
 }
 
+// SetContextValidationFunc sets the context aware function to be used for a given
+// validation constraint. The function will be added to the default validator
+func SetContextValidationFunc(name string, vf ContextValidatorFunc) error {
+	return defaultValidator.SetContextValidationFunc(name, vf)
+}
+
+// SetValidationCompiler sets the compiler to be used for a given validation
+// constraint on the default validator
+func SetValidationCompiler(name string, cf CompilerFunc) error {
+	return defaultValidator.SetValidationCompiler(name, cf)
+}
+
+// SetTranslator allows you to change the translator used by the default validator
+func SetTranslator(translator *Translator) {
+	defaultValidator.SetTranslator(translator)
+}
+
+// TranslateErrors renders the errors returned by the default validator as
+// messages in the locale indexed by the field name.
+func TranslateErrors(locale string, err error) map[string][]string {
+	return defaultValidator.Translator().TranslateErrors(locale, err)
+}
+
 // Validate validates the fields of a struct based  on 'validator' tags and returns
 // the first validation error found per field name.
 func Validate(v interface{}) error {
 	return defaultValidator.Validate(v)
 }
 
+// ValidateCtx validates the fields of a struct like Validate and passes the context
+// to the context aware validators. Validation stops when the context is done.
+func ValidateCtx(ctx context.Context, v interface{}) error {
+	return defaultValidator.ValidateCtx(ctx, v)
+}
+
 // ValidateAll validates the fields of a struct based  on 'validator' tags and returns
 // errors found indexed by the field name.
 func ValidateAll(v interface{}) error {
 	return defaultValidator.ValidateAll(v)
 }
 
+// ValidateAllCtx validates the fields of a struct like ValidateAll and passes the context
+// to the context aware validators. Validation stops when the context is done.
+func ValidateAllCtx(ctx context.Context, v interface{}) error {
+	return defaultValidator.ValidateAllCtx(ctx, v)
+}
+
+// ValidatePartial validates only the fields of a struct found by the dotted paths
+// and returns the first validation error found per field name.
+func ValidatePartial(v interface{}, paths ...string) error {
+	return defaultValidator.ValidatePartial(v, paths...)
+}
+
+// ValidateExcept validates the fields of a struct like Validate but leaves out the
+// fields found by the dotted paths.
+func ValidateExcept(v interface{}, paths ...string) error {
+	return defaultValidator.ValidateExcept(v, paths...)
+}
+
 // Valid validates a value based on the provided tags and returns the first validation error found or nil.
 func Valid(val interface{}, tags string) error {
 	return defaultValidator.Valid(val, tags)
 }
 
+// ValidCtx validates a value like Valid and passes the context to the context aware validators.
+func ValidCtx(ctx context.Context, val interface{}, tags string) error {
+	return defaultValidator.ValidCtx(ctx, val, tags)
+}
+
 // ValidAll validates a value based on the provided tags and returns errors found or nil.
 func ValidAll(val interface{}, tags string) error {
 	return defaultValidator.ValidAll(val, tags)
 }
 
+// ValidAllCtx validates a value like ValidAll and passes the context to the context aware validators.
+func ValidAllCtx(ctx context.Context, val interface{}, tags string) error {
+	return defaultValidator.ValidAllCtx(ctx, val, tags)
+}
+
 // SetNameResolver allows you to change the way field names are resolved
 func (mv *validator) SetNameResolver(resolver NameResolverFunc) {
-	mv.nameResolver = resolver
-	mv.resetCache()
+	mv.mustConfigure(func(cfg *config) {
+		cfg.nameResolver = resolver
+	})
+}
+
+// SetTranslator allows you to change the translator used to render errors as messages
+func (mv *validator) SetTranslator(translator *Translator) {
+	mv.mustConfigure(func(cfg *config) {
+		cfg.translator = translator
+	})
+}
+
+// Translator returns the translator used to render errors as messages, the
+// messages of the translator of a frozen validator can not be changed
+func (mv *validator) Translator() *Translator {
+	return mv.config().translator
 }
 
 // SetTag allows you to change the validatorTag name used in structs
 func (mv *validator) SetTag(tag string) {
-	mv.tagName = tag
-	mv.resetCache()
+	mv.mustConfigure(func(cfg *config) {
+		cfg.tagName = tag
+	})
 }
 
 // WithTag creates a new Validator based on the current validator with the new validatorTag name.
+// The new Validator shares nothing with the current validator and is not frozen.
 func (mv *validator) WithTag(tag string) Validator {
 	v := mv.copy()
 	v.SetTag(tag)
 	return v
 }
 
-// copy creates a duplicate of the current validator and returns the new instance
-func (mv *validator) copy() Validator {
-	return &validator{
-		tagName:         mv.tagName,
-		validationFuncs: mv.validationFuncs,
-		structRules:     mv.structRules,
-		nameResolver:    mv.nameResolver,
+// SetValidationFunc sets the function to be used for a given validation constraint.
+// Calling this function with nil validatorFunction (vf) is the same as removing
+// the constraint function from the list.
+func (mv *validator) SetValidationFunc(name string, vf ValidatorFunc) error {
+	if name == "" {
+		return errors.New("name cannot be empty")
 	}
+	return mv.configure(func(cfg *config) {
+		cfg.removeValidator(name)
+		if vf != nil {
+			cfg.validationFuncs[name] = vf
+		}
+	})
 }
 
-// SetValidationFunc sets the function to be used for a given validation constraint.
+// SetContextValidationFunc sets the context aware function to be used for a given
+// validation constraint. It replaces any ValidatorFunc with the same name.
 // Calling this function with nil validatorFunction (vf) is the same as removing
 // the constraint function from the list.
-func (mv *validator) SetValidationFunc(name string, vf ValidatorFunc) error {
+func (mv *validator) SetContextValidationFunc(name string, vf ContextValidatorFunc) error {
 	if name == "" {
 		return errors.New("name cannot be empty")
 	}
-	if vf == nil {
-		delete(mv.validationFuncs, name)
-		return nil
+	return mv.configure(func(cfg *config) {
+		cfg.removeValidator(name)
+		if vf != nil {
+			cfg.contextValidationFuncs[name] = vf
+		}
+	})
+}
+
+// SetValidationCompiler sets the compiler to be used for a given validation
+// constraint. The compiler parses the parameters once when the tags are parsed
+// and returns the function validating the values. It replaces any ValidatorFunc
+// or ContextValidatorFunc with the same name. Calling this function with nil
+// compiler (cf) is the same as removing the constraint function from the list.
+func (mv *validator) SetValidationCompiler(name string, cf CompilerFunc) error {
+	if name == "" {
+		return errors.New("name cannot be empty")
 	}
-	mv.validationFuncs[name] = vf
-	return nil
+	return mv.configure(func(cfg *config) {
+		cfg.removeValidator(name)
+		if cf != nil {
+			cfg.compilers[name] = cf
+		}
+	})
 }
 
 // Validate validates the fields of a struct based on 'validator' tags and returns
 // the first valiadtion errors found indexed per field name.
 func (mv *validator) Validate(v interface{}) error {
-	return mv.validate(v, true)
+	return mv.validate(context.Background(), v, true)
+}
+
+// ValidateCtx validates the fields of a struct like Validate and passes the context
+// to the context aware validators. Validation stops when the context is done.
+func (mv *validator) ValidateCtx(ctx context.Context, v interface{}) error {
+	return mv.validate(ctx, v, true)
 }
 
 // ValidateAll validates the fields of a struct based on 'validator' tags and returns
 // errors found indexed by the field name.
 func (mv *validator) ValidateAll(v interface{}) error {
-	return mv.validate(v, false)
+	return mv.validate(context.Background(), v, false)
 }
 
-func (mv *validator) validate(v interface{}, stopOnError bool) error {
+// ValidateAllCtx validates the fields of a struct like ValidateAll and passes the context
+// to the context aware validators. Validation stops when the context is done.
+func (mv *validator) ValidateAllCtx(ctx context.Context, v interface{}) error {
+	return mv.validate(ctx, v, false)
+}
+
+// ValidatePartial validates only the fields of a struct found by the dotted paths
+// and returns the first validation error found per field name. A path segment can
+// be the Go field name or the resolved name.
+func (mv *validator) ValidatePartial(v interface{}, paths ...string) error {
+	return mv.validate(WithPaths(context.Background(), paths...), v, true)
+}
+
+// ValidateExcept validates the fields of a struct like Validate but leaves out the
+// fields found by the dotted paths.
+func (mv *validator) ValidateExcept(v interface{}, paths ...string) error {
+	return mv.validate(WithoutPaths(context.Background(), paths...), v, true)
+}
+
+func (mv *validator) validate(ctx context.Context, v interface{}, stopOnError bool) error {
 	sv := reflect.ValueOf(v)
 
 	//nil pointer not type found
@@ -216,29 +440,34 @@ func (mv *validator) validate(v interface{}, stopOnError bool) error {
 		return ErrUnsupported
 	}
 
-	if sv.Kind() == reflect.Ptr && !sv.IsNil() {
-		return mv.validate(sv.Elem().Interface(), stopOnError)
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	selection := pathsFromContext(ctx)
+	vs := &validation{ctx: ctx, stopOnError: stopOnError, paths: selection.paths, exclude: selection.exclude}
+	for sv.Kind() == reflect.Ptr && !sv.IsNil() {
+		if sv.Elem().Kind() == reflect.Struct {
+			vs.enter(visit{ptr: sv.Pointer(), typ: sv.Elem().Type()})
+		}
+		sv = sv.Elem()
 	}
 
 	if sv.Kind() != reflect.Struct {
 		return ErrUnsupported
 	}
 
-	mv.mu.RLock()
-	rules, ok := mv.structRules[sv.Type()]
-	mv.mu.RUnlock()
-	if !ok {
-		mv.mu.Lock()
-		defer mv.mu.Unlock()
-		r, err := mv.parseStruct(sv.Type())
-		if err != nil {
-			return err
-		}
-		mv.structRules[sv.Type()] = r
-		rules = r
+	rules, err := mv.structRulesFor(sv.Type(), groupsFromContext(ctx))
+	if err != nil {
+		return err
 	}
 
-	if errs := rules.Validate(sv, stopOnError); len(errs) > 0 {
+	errs := rules.Validate(sv, vs)
+	if vs.err != nil {
+		return vs.err
+	}
+
+	if len(errs) > 0 {
 		return errs
 	}
 	return nil
@@ -246,56 +475,71 @@ func (mv *validator) validate(v interface{}, stopOnError bool) error {
 
 // Valid validates a value based on the provided tags and returns the first validation error found or nil.
 func (mv *validator) Valid(val interface{}, tags string) error {
+	return mv.ValidCtx(context.Background(), val, tags)
+}
+
+// ValidCtx validates a value like Valid and passes the context to the context aware validators.
+func (mv *validator) ValidCtx(ctx context.Context, val interface{}, tags string) error {
 	if tags == "-" {
 		return nil
 	}
 	v := reflect.ValueOf(val)
 	if v.Kind() == reflect.Ptr && !v.IsNil() {
-		return mv.Valid(v.Elem().Interface(), tags)
+		return mv.ValidCtx(ctx, v.Elem().Interface(), tags)
 	}
 
 	if v.Kind() == reflect.Invalid {
-		return mv.validateVar(nil, tags, true)
+		return mv.validateVar(ctx, nil, tags, true)
 	}
-	return mv.validateVar(val, tags, true)
+	return mv.validateVar(ctx, val, tags, true)
 }
 
 // ValidAll validates a value based on the provided tags and returns errors found or nil.
 func (mv *validator) ValidAll(val interface{}, tags string) error {
+	return mv.ValidAllCtx(context.Background(), val, tags)
+}
+
+// ValidAllCtx validates a value like ValidAll and passes the context to the context aware validators.
+func (mv *validator) ValidAllCtx(ctx context.Context, val interface{}, tags string) error {
 	if tags == "-" {
 		return nil
 	}
 	v := reflect.ValueOf(val)
 	if v.Kind() == reflect.Ptr && !v.IsNil() {
-		return mv.ValidAll(v.Elem().Interface(), tags)
+		return mv.ValidAllCtx(ctx, v.Elem().Interface(), tags)
 	}
 
 	if v.Kind() == reflect.Invalid {
-		return mv.validateVar(nil, tags, false)
+		return mv.validateVar(ctx, nil, tags, false)
 	}
-	return mv.validateVar(val, tags, false)
-}
-
-func (mv *validator) resetCache() {
-	mv.mu.Lock()
-	defer mv.mu.Unlock()
-	mv.structRules = make(structRules, 0)
+	return mv.validateVar(ctx, val, tags, false)
 }
 
 // validateVar validates a single variable
-func (mv *validator) validateVar(v interface{}, tag string, stopOnError bool) error {
-	tags, err := mv.parseTags(tag)
+func (mv *validator) validateVar(ctx context.Context, v interface{}, tag string, stopOnError bool) error {
+	tags, err := mv.config().parseTags(tag, groupsFromContext(ctx))
 	if err != nil {
 		// unknown validatorTag found.
 		return err
 	}
+	return runVar(ctx, v, tag, tags, stopOnError)
+}
+
+// runVar runs the parsed validators of the tag against a single variable
+func runVar(ctx context.Context, v interface{}, tag string, tags []validatorTag, stopOnError bool) error {
+	vs := &validation{ctx: ctx, field: &rule{Tag: tag}}
+
 	var errs ErrorList
 	for _, t := range tags {
-		if err := t.Fn(v, t.Args); err != nil {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if err := t.call(v, "", vs); err != nil {
 			if err == errOmitEmpty {
 				return nil
 			}
-			errs = append(errs, err)
+			errs = append(errs, vs.fieldError(&t, v, "", "", err))
 
 			if stopOnError == true {
 				return errs
@@ -306,30 +550,164 @@ func (mv *validator) validateVar(v interface{}, tag string, stopOnError bool) er
 }
 
 // parseTags parses all individual tags found within a struct validatorTag and
-// resolve the validator function
-func (mv *validator) parseTags(t string) ([]validatorTag, error) {
+// resolve the validator function. Tags scoped to groups that are not active are
+// left out.
+func (cfg *config) parseTags(t string, groups groupSet) ([]validatorTag, error) {
+	params, err := tags.Parse(t)
+	if err != nil {
+		return nil, ErrSyntax
+	}
+
+	params, err = groups.filterGroups(params)
+	if err != nil {
+		return nil, err
+	}
+	return cfg.compileTags(params)
+}
+
+// parseFieldTags parses the tags of a struct field into the validators for the
+// field, the validators for the map keys found between the keys and endkeys
+// markers and the validators for the elements found after a dive marker. Tags
+// scoped to groups that are not active are left out.
+func (cfg *config) parseFieldTags(t string, groups groupSet) (*fieldTags, error) {
 	params, err := tags.Parse(t)
 	if err != nil {
 		return nil, ErrSyntax
 	}
 
+	params, err = groups.filterGroups(params)
+	if err != nil {
+		return nil, err
+	}
+	return cfg.compileFieldTags(params)
+}
+
+// compileFieldTags resolves the validator functions for one level of field tags
+// and descends into the next level when a dive marker is found
+func (cfg *config) compileFieldTags(params []tags.Param) (*fieldTags, error) {
+	var fieldParams, keyParams, elemParams []tags.Param
+	inKeys, dive := false, false
+	for i, param := range params {
+		switch {
+		case param.Name == diveTag && !inKeys:
+			dive = true
+			elemParams = params[i+1:]
+		case param.Name == keysTag && !inKeys:
+			inKeys = true
+		case param.Name == endKeysTag && inKeys:
+			inKeys = false
+		case param.Name == keysTag || param.Name == endKeysTag || param.Name == diveTag:
+			return nil, ErrSyntax
+		case inKeys:
+			keyParams = append(keyParams, param)
+		default:
+			fieldParams = append(fieldParams, param)
+		}
+
+		if dive {
+			break
+		}
+	}
+
+	if inKeys {
+		return nil, ErrSyntax
+	}
+
+	var err error
+	ft := &fieldTags{}
+	if ft.validators, err = cfg.compileTags(fieldParams); err != nil {
+		return nil, err
+	}
+
+	if ft.keys, err = cfg.compileTags(keyParams); err != nil {
+		return nil, err
+	}
+
+	if dive {
+		if ft.elem, err = cfg.compileFieldTags(elemParams); err != nil {
+			return nil, err
+		}
+	}
+	return ft, nil
+}
+
+// compileTags resolves the validator function for every parsed tag param
+func (cfg *config) compileTags(params []tags.Param) ([]validatorTag, error) {
 	tags := make([]validatorTag, 0, len(params))
 	for _, param := range params {
-		validatorFunc, found := mv.validationFuncs[param.Name]
+		if compiler, found := cfg.compilers[param.Name]; found {
+			validatorFunc, err := compiler(param.Args)
+			if err != nil {
+				return nil, err
+			}
+
+			tags = append(tags, validatorTag{
+				Param: param,
+				Fn:    validatorFunc,
+			})
+			continue
+		}
+
+		if validatorFunc, found := cfg.validationFuncs[param.Name]; found {
+			tags = append(tags, validatorTag{
+				Param: param,
+				Fn:    validatorFunc,
+			})
+			continue
+		}
+
+		contextValidatorFunc, found := cfg.contextValidationFuncs[param.Name]
 		if !found {
 			return nil, ErrUnknownTag
 		}
 
 		tags = append(tags, validatorTag{
 			Param: param,
-			Fn:    validatorFunc,
+			CtxFn: contextValidatorFunc,
 		})
 	}
 	return tags, nil
 }
 
-// parseStruct will extract all the validation rules from the given structure
-func (mv *validator) parseStruct(t reflect.Type) (rules, error) {
+// compilation holds the state while compiling the rules of a structure
+type compilation struct {
+	cfg     *config                 // configuration the rules are compiled with
+	cache   *ruleCache              // cache with the rules compiled before
+	groups  groupSet                // active validation groups
+	pending map[reflect.Type]*rules // compiled structures not yet stored in the cache
+}
+
+// structRulesFor returns the rules of the structure for the active groups from
+// the cache, the structure is compiled when not found
+func (mv *validator) structRulesFor(t reflect.Type, groups groupSet) (*rules, error) {
+	cache := mv.ruleCache()
+	return cache.getOrCompile(t, groups, func() (*rules, map[reflect.Type]*rules, error) {
+		return mv.parseStruct(t, groups, cache)
+	})
+}
+
+// parseStruct will extract all the validation rules for the active groups from
+// the given structure. The rules of the structure and all the nested structures
+// compiled are returned to be stored in the cache.
+func (mv *validator) parseStruct(t reflect.Type, groups groupSet, cache *ruleCache) (*rules, map[reflect.Type]*rules, error) {
+	c := &compilation{
+		cfg:     cache.cfg,
+		cache:   cache,
+		groups:  groups,
+		pending: make(map[reflect.Type]*rules),
+	}
+
+	rules, err := mv.compileStruct(t, c)
+	if err != nil {
+		return nil, nil, err
+	}
+	return rules, c.pending, nil
+}
+
+// compileStruct compiles the rules for the fields of the structure. The rules are
+// registered as pending before the fields are compiled, a structure referring
+// to itself will resolve to the same rules.
+func (mv *validator) compileStruct(t reflect.Type, c *compilation) (*rules, error) {
 	if t.Kind() == reflect.Ptr {
 		t = t.Elem()
 	}
@@ -338,10 +716,14 @@ func (mv *validator) parseStruct(t reflect.Type) (rules, error) {
 		return nil, ErrUnsupported
 	}
 
-	rules := rules{}
+	rules := &rules{}
+	c.pending[t] = rules
 	for i := 0; i < t.NumField(); i++ {
 		sf := t.Field(i)
-		tag := sf.Tag.Get(mv.tagName)
+		tag, err := c.cfg.fieldTag(t, sf)
+		if err != nil {
+			return nil, err
+		}
 
 		if tag == "-" {
 			continue
@@ -351,53 +733,118 @@ func (mv *validator) parseStruct(t reflect.Type) (rules, error) {
 			continue
 		}
 
-		fieldName := mv.nameResolver(sf)
+		fieldName := c.cfg.nameResolver(sf)
 		rule := rule{
 			Name:       fieldName,
 			FieldIndex: i,
-			IsSlice:    false,
-			IsStruct:   false,
+			Field:      sf,
+			Tag:        tag,
 		}
 
+		ft := &fieldTags{}
 		if tag != "" {
 			//extract the validator properties
-			validatorTags, err := mv.parseTags(tag)
+			var err error
+			ft, err = c.cfg.parseFieldTags(tag, c.groups)
 			if err != nil {
 				// unknown validatorTag found.
 				return nil, err
 			}
+		}
 
-			//Add validatorTags to rules
-			rule.Validators = validatorTags
-
+		if err := mv.compileRule(&rule, sf.Type, ft, c); err != nil {
+			return nil, err
 		}
 
-		st := sf.Type
-		if st.Kind() == reflect.Slice {
-			rule.IsSlice = true
-			st = st.Elem()
+		*rules = append(*rules, rule)
+	}
+
+	return rules, nil
+}
+
+// compileRule sets up the rule for a value of the given type with the validators
+// from the field tags. The rule descends into the elements of slices, arrays and
+// maps when the tags dive into them, or when the elements are structures.
+func (mv *validator) compileRule(r *rule, t reflect.Type, ft *fieldTags, c *compilation) error {
+	r.Validators = ft.validators
+	r.KeyValidators = ft.keys
+
+	if err := c.cfg.checkParams(r.Validators, t); err != nil {
+		return err
+	}
+
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		r.IsSlice = true
+	case reflect.Map:
+		r.IsMap = true
+	}
+
+	if len(r.KeyValidators) > 0 && !r.IsMap {
+		return ErrUnsupported
+	}
+
+	if r.IsMap {
+		if err := c.cfg.checkParams(r.KeyValidators, t.Key()); err != nil {
+			return err
 		}
+	}
 
-		if st.Kind() == reflect.Ptr {
-			st = st.Elem()
+	if ft.elem != nil {
+		if !r.IsSlice && !r.IsMap {
+			return ErrUnsupported
 		}
+		r.Elem = &rule{}
+		return mv.compileRule(r.Elem, t.Elem(), ft.elem, c)
+	}
 
-		if st.Kind() == reflect.Struct {
-			subset, ok := mv.structRules[st]
-			if !ok {
-				var err error
-				subset, err = mv.parseStruct(st)
-				if err != nil {
-					return nil, err
-				}
-				mv.structRules[st] = subset
-			}
-			rule.IsStruct = true
-			rule.Subset = subset
+	if (r.IsSlice || r.IsMap) && holdsStruct(t.Elem()) {
+		r.Elem = &rule{}
+		return mv.compileRule(r.Elem, t.Elem(), &fieldTags{}, c)
+	}
+
+	if t.Kind() == reflect.Struct {
+		subset, err := mv.structSubset(t, c)
+		if err != nil {
+			return err
 		}
+		r.IsStruct = true
+		r.Subset = subset
+	}
+	return nil
+}
 
-		rules = append(rules, rule)
+// structSubset returns the rules for a nested structure from the cache or the
+// pending rules, or compiles the structure when not found
+func (mv *validator) structSubset(t reflect.Type, c *compilation) (*rules, error) {
+	if subset, ok := c.cache.get(t, c.groups); ok {
+		return subset, nil
 	}
 
-	return rules, nil
+	if subset, ok := c.pending[t]; ok {
+		return subset, nil
+	}
+	return mv.compileStruct(t, c)
+}
+
+// holdsStruct reports whether a structure is found after unwrapping all the
+// pointer, slice, array and map layers of the type
+func holdsStruct(t reflect.Type) bool {
+	seen := map[reflect.Type]bool{}
+	for !seen[t] {
+		seen[t] = true
+		switch t.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+			t = t.Elem()
+		case reflect.Struct:
+			return true
+		default:
+			return false
+		}
+	}
+	return false
 }
@@ -1,9 +1,13 @@
 package validate_test
 
 import (
+	"context"
+	"errors"
 	"github.com/mbict/go-validate"
 	. "gopkg.in/check.v1"
+	"reflect"
 	"testing"
+	"time"
 )
 
 func Test(t *testing.T) {
@@ -214,6 +218,439 @@ func (vs *ValidatorSuite) TestValidateAllSlice(c *C) {
 	c.Assert(err, IsNil)
 }
 
+func (vs *ValidatorSuite) TestValidateAllMap(c *C) {
+	var test = []struct {
+		A map[string]testSimple  `validate:"required"`
+		B map[string]*testSimple `validate:"max(2);keys;min(3);endkeys"`
+	}{
+		{
+			A: nil,
+			B: map[string]*testSimple{"a": {11}, "bb": {12}, "ccc": {13}},
+		}, {
+			A: map[string]testSimple{"foo": {1}, "bar": {11}},
+			B: map[string]*testSimple{"foo": {3}, "bar": nil},
+		}, {
+			A: map[string]testSimple{"foo": {11}},
+			B: map[string]*testSimple{"foo": {11}},
+		},
+	}
+
+	//error, nil map and invalid keys
+	err := validate.ValidateAll(test[0])
+	c.Assert(err, NotNil)
+
+	errs, ok := err.(validate.Errors)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 4)
+	c.Assert(errs["A"], HasLen, 1)
+	c.Assert(errs["A"], HasError, validate.ErrRequired)
+	c.Assert(errs["B"], HasLen, 1)
+	c.Assert(errs["B"], HasError, validate.ErrMax)
+	c.Assert(errs["B[a]"], HasLen, 1)
+	c.Assert(errs["B[a]"], HasError, validate.ErrMin)
+	c.Assert(errs["B[bb]"], HasLen, 1)
+	c.Assert(errs["B[bb]"], HasError, validate.ErrMin)
+
+	//error, invalid map values
+	err = validate.ValidateAll(test[1])
+	c.Assert(err, NotNil)
+
+	errs, ok = err.(validate.Errors)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 2)
+	c.Assert(errs["A.foo.A"], HasLen, 1)
+	c.Assert(errs["A.foo.A"], HasError, validate.ErrMin)
+	c.Assert(errs["B.foo.A"], HasLen, 1)
+	c.Assert(errs["B.foo.A"], HasError, validate.ErrMin)
+
+	//should pass
+	err = validate.ValidateAll(test[2])
+	c.Assert(err, IsNil)
+}
+
+func (vs *ValidatorSuite) TestValidateAllMapKeysOnNonMapShouldError(c *C) {
+	test := struct {
+		A []string `validate:"keys;min(3);endkeys"`
+	}{}
+
+	err := validate.ValidateAll(test)
+	c.Assert(err, Equals, validate.ErrUnsupported)
+}
+
+func (vs *ValidatorSuite) TestValidateAllDive(c *C) {
+	var test = []struct {
+		A []string         `validate:"min(1);dive;email;max(16)"`
+		B [][]string       `validate:"dive;min(1);dive;required"`
+		C map[string]int   `validate:"dive;max(10)"`
+		D [2]string        `validate:"dive;len(2)"`
+		E []map[string]int `validate:"dive;keys;min(2);endkeys;dive;min(1)"`
+		F []*testSimple    `validate:"dive;required"`
+	}{
+		{
+			A: []string{},
+		}, {
+			A: []string{"foo@bar.com", "foo", "foo.bar.baz@example.com"},
+			B: [][]string{{"a", ""}, {}},
+			C: map[string]int{"a": 1, "b": 11},
+			D: [2]string{"aa", "b"},
+			E: []map[string]int{{"a": 0, "bb": 1}},
+			F: []*testSimple{nil, {1}},
+		}, {
+			A: []string{"foo@bar.com"},
+			B: [][]string{{"a"}},
+			C: map[string]int{"a": 1},
+			D: [2]string{"aa", "bb"},
+			E: []map[string]int{{"aa": 1}},
+			F: []*testSimple{{11}},
+		},
+	}
+
+	//error, empty container
+	err := validate.ValidateAll(test[0])
+	c.Assert(err, NotNil)
+
+	errs, ok := err.(validate.Errors)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 3)
+	c.Assert(errs["A"], HasLen, 1)
+	c.Assert(errs["A"], HasError, validate.ErrMin)
+	c.Assert(errs["D.0"], HasLen, 1)
+	c.Assert(errs["D.0"], HasError, validate.ErrLen)
+	c.Assert(errs["D.1"], HasLen, 1)
+	c.Assert(errs["D.1"], HasError, validate.ErrLen)
+
+	//error, invalid elements
+	err = validate.ValidateAll(test[1])
+	c.Assert(err, NotNil)
+
+	errs, ok = err.(validate.Errors)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 10)
+	c.Assert(errs["A.1"], HasLen, 1)
+	c.Assert(errs["A.1"], HasError, validate.ErrEmail)
+	c.Assert(errs["A.2"], HasLen, 1)
+	c.Assert(errs["A.2"], HasError, validate.ErrMax)
+	c.Assert(errs["B.0.1"], HasLen, 1)
+	c.Assert(errs["B.0.1"], HasError, validate.ErrRequired)
+	c.Assert(errs["B.1"], HasLen, 1)
+	c.Assert(errs["B.1"], HasError, validate.ErrMin)
+	c.Assert(errs["C.b"], HasLen, 1)
+	c.Assert(errs["C.b"], HasError, validate.ErrMax)
+	c.Assert(errs["D.1"], HasLen, 1)
+	c.Assert(errs["D.1"], HasError, validate.ErrLen)
+	c.Assert(errs["E.0[a]"], HasLen, 1)
+	c.Assert(errs["E.0[a]"], HasError, validate.ErrMin)
+	c.Assert(errs["E.0.a"], HasLen, 1)
+	c.Assert(errs["E.0.a"], HasError, validate.ErrMin)
+	c.Assert(errs["F.0"], HasLen, 1)
+	c.Assert(errs["F.0"], HasError, validate.ErrRequired)
+	c.Assert(errs["F.1.A"], HasLen, 1)
+	c.Assert(errs["F.1.A"], HasError, validate.ErrMin)
+	c.Assert(errs["E.0.bb"], IsNil)
+
+	//should pass
+	err = validate.ValidateAll(test[2])
+	c.Assert(err, IsNil)
+}
+
+func (vs *ValidatorSuite) TestValidateAllDiveOnNonContainerShouldError(c *C) {
+	test := struct {
+		A string `validate:"dive;min(3)"`
+	}{}
+
+	err := validate.ValidateAll(test)
+	c.Assert(err, Equals, validate.ErrUnsupported)
+}
+
+func (vs *ValidatorSuite) TestValidateAllNestedPointersSlicesAndArrays(c *C) {
+	invalid := &testSimple{1}
+	valid := &testSimple{11}
+
+	var test = []struct {
+		A [2]testSimple
+		B *[]testSimple
+		C []**testSimple
+		D **testSimple
+		E [][]*testSimple
+	}{
+		{
+			B: nil,
+			C: []**testSimple{nil, new(*testSimple)},
+			D: new(*testSimple),
+			E: [][]*testSimple{nil, {nil}},
+		}, {
+			A: [2]testSimple{{11}, {1}},
+			B: &[]testSimple{{1}},
+			C: []**testSimple{&invalid, &valid},
+			D: &invalid,
+			E: [][]*testSimple{{valid}, {valid, invalid}},
+		}, {
+			A: [2]testSimple{{11}, {12}},
+			B: &[]testSimple{{11}},
+			C: []**testSimple{&valid},
+			D: &valid,
+			E: [][]*testSimple{{valid}},
+		},
+	}
+
+	//error, zero values and nil pointers
+	err := validate.ValidateAll(test[0])
+	c.Assert(err, NotNil)
+
+	errs, ok := err.(validate.Errors)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 2)
+	c.Assert(errs["A.0.A"], HasLen, 1)
+	c.Assert(errs["A.0.A"], HasError, validate.ErrMin)
+	c.Assert(errs["A.1.A"], HasLen, 1)
+	c.Assert(errs["A.1.A"], HasError, validate.ErrMin)
+
+	//error, invalid nested structures
+	err = validate.ValidateAll(test[1])
+	c.Assert(err, NotNil)
+
+	errs, ok = err.(validate.Errors)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 5)
+	c.Assert(errs["A.1.A"], HasError, validate.ErrMin)
+	c.Assert(errs["B.0.A"], HasError, validate.ErrMin)
+	c.Assert(errs["C.0.A"], HasError, validate.ErrMin)
+	c.Assert(errs["D.A"], HasError, validate.ErrMin)
+	c.Assert(errs["E.1.1.A"], HasError, validate.ErrMin)
+
+	//should pass
+	err = validate.ValidateAll(test[2])
+	c.Assert(err, IsNil)
+}
+
+type testNode struct {
+	Name     string `validate:"required"`
+	Parent   *testNode
+	Children []*testNode `validate:"max(2)"`
+}
+
+func (vs *ValidatorSuite) TestValidateAllSelfReferencingStruct(c *C) {
+	root := &testNode{Name: "root"}
+	child := &testNode{Parent: root}
+	root.Children = []*testNode{child, {Name: "b", Parent: root, Children: []*testNode{{}}}}
+
+	//error, nested invalid nodes
+	err := validate.ValidateAll(root)
+	c.Assert(err, NotNil)
+
+	errs, ok := err.(validate.Errors)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 2)
+	c.Assert(errs["Children.0.Name"], HasLen, 1)
+	c.Assert(errs["Children.0.Name"], HasError, validate.ErrRequired)
+	c.Assert(errs["Children.1.Children.0.Name"], HasLen, 1)
+	c.Assert(errs["Children.1.Children.0.Name"], HasError, validate.ErrRequired)
+
+	//error, cycle started from a nested node
+	err = validate.ValidateAll(child)
+	c.Assert(err, NotNil)
+
+	errs, ok = err.(validate.Errors)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 2)
+	c.Assert(errs["Name"], HasError, validate.ErrRequired)
+	c.Assert(errs["Parent.Children.1.Children.0.Name"], HasError, validate.ErrRequired)
+
+	//should pass
+	child.Name = "a"
+	root.Children[1].Children[0].Name = "c"
+	err = validate.ValidateAll(root)
+	c.Assert(err, IsNil)
+}
+
+type testPeriod struct {
+	Start time.Time
+	End   time.Time `validate:"gtfield(Start)"`
+}
+
+func (vs *ValidatorSuite) TestValidateAllCrossField(c *C) {
+	now := time.Now()
+	type testCrossField struct {
+		Password        string
+		PasswordConfirm string `validate:"eqfield(Password)"`
+		Username        string `validate:"nefield(Password)"`
+		Min             int
+		Max             float64 `validate:"gtfield(Min)"`
+		Limit           *uint   `validate:"ltfield(Period.End)"`
+		Period          testPeriod
+		Deadline        time.Time `validate:"ltfield(Period.End);gtfield(Period.Start)"`
+	}
+
+	one, ten := uint(1), uint(10)
+	var test = []testCrossField{
+		{
+			Password:        "secret",
+			PasswordConfirm: "secrets",
+			Username:        "secret",
+			Min:             10,
+			Max:             10,
+			Period:          testPeriod{Start: now, End: now},
+			Deadline:        now,
+		}, {
+			Password:        "secret",
+			PasswordConfirm: "secret",
+			Username:        "foo",
+			Min:             10,
+			Max:             10.5,
+			Period:          testPeriod{Start: now, End: now.Add(time.Hour)},
+			Deadline:        now.Add(time.Minute),
+		},
+	}
+
+	//error, all fields invalid
+	err := validate.ValidateAll(test[0])
+	c.Assert(err, NotNil)
+
+	errs, ok := err.(validate.Errors)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 5)
+	c.Assert(errs["PasswordConfirm"], HasLen, 1)
+	c.Assert(errs["PasswordConfirm"], HasError, validate.ErrEqField)
+	c.Assert(errs["Username"], HasLen, 1)
+	c.Assert(errs["Username"], HasError, validate.ErrNeField)
+	c.Assert(errs["Max"], HasLen, 1)
+	c.Assert(errs["Max"], HasError, validate.ErrGtField)
+	c.Assert(errs["Period.End"], HasLen, 1)
+	c.Assert(errs["Period.End"], HasError, validate.ErrGtField)
+	c.Assert(errs["Deadline"], HasLen, 2)
+	c.Assert(errs["Deadline"], HasError, validate.ErrLtField)
+	c.Assert(errs["Deadline"], HasError, validate.ErrGtField)
+
+	//should pass
+	err = validate.ValidateAll(test[1])
+	c.Assert(err, IsNil)
+
+	//error, compare uint with time
+	test[1].Limit = &one
+	err = validate.ValidateAll(test[1])
+	c.Assert(err, NotNil)
+
+	errs, ok = err.(validate.Errors)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["Limit"], HasError, validate.ErrUnsupported)
+
+	//error, unknown field
+	test2 := struct {
+		A int `validate:"ltfield(B)"`
+		C *int
+		D *uint `validate:"gtfield(C);eqfield(C)"`
+	}{D: &ten}
+	err = validate.ValidateAll(test2)
+	c.Assert(err, NotNil)
+
+	errs, ok = err.(validate.Errors)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 2)
+	c.Assert(errs["A"], HasError, validate.ErrBadParameter)
+	c.Assert(errs["D"], HasLen, 1)
+	c.Assert(errs["D"], HasError, validate.ErrEqField)
+
+	//error, unexported fields can not be referred to
+	test3 := struct {
+		A        time.Time `validate:"gtfield(deadline)"`
+		B        string    `validate:"eqfield(password)"`
+		deadline time.Time
+		password string
+	}{A: now, deadline: now}
+	err = validate.ValidateAll(test3)
+	c.Assert(err, NotNil)
+
+	errs, ok = err.(validate.Errors)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 2)
+	c.Assert(errs["A"], HasError, validate.ErrBadParameter)
+	c.Assert(errs["B"], HasError, validate.ErrBadParameter)
+}
+
+func (vs *ValidatorSuite) TestValidateAllConditionalRequired(c *C) {
+	type testConditional struct {
+		AccountType string
+		CompanyName string `validate:"required_if(AccountType,business,enterprise);min(3)"`
+		Email       string
+		Phone       string `validate:"required_without(Email)"`
+		Street      string
+		City        string `validate:"required_with(Street);alpha_dash"`
+		VatNumber   string `validate:"excluded_if(AccountType,personal);min(3)"`
+	}
+
+	var test = []testConditional{
+		{
+			AccountType: "business",
+			CompanyName: "",
+			Street:      "Main street",
+			VatNumber:   "NL01",
+		}, {
+			AccountType: "personal",
+			CompanyName: "ab",
+			Email:       "foo@bar.com",
+			VatNumber:   "NL",
+		}, {
+			AccountType: "personal",
+			Email:       "foo@bar.com",
+		}, {
+			AccountType: "enterprise",
+			CompanyName: "foo",
+			Phone:       "0123",
+			Street:      "Main street",
+			City:        "Amsterdam",
+			VatNumber:   "NL01",
+		},
+	}
+
+	//error, conditions met and fields empty
+	err := validate.ValidateAll(test[0])
+	c.Assert(err, NotNil)
+
+	errs, ok := err.(validate.Errors)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 3)
+	c.Assert(errs["CompanyName"], HasLen, 2)
+	c.Assert(errs["CompanyName"], HasError, validate.ErrRequired)
+	c.Assert(errs["CompanyName"], HasError, validate.ErrMin)
+	c.Assert(errs["Phone"], HasLen, 1)
+	c.Assert(errs["Phone"], HasError, validate.ErrRequired)
+	c.Assert(errs["City"], HasLen, 1)
+	c.Assert(errs["City"], HasError, validate.ErrRequired)
+
+	//error, excluded field set, remaining rules skipped when condition not met
+	err = validate.ValidateAll(test[1])
+	c.Assert(err, NotNil)
+
+	errs, ok = err.(validate.Errors)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 1)
+	c.Assert(errs["VatNumber"], HasLen, 2)
+	c.Assert(errs["VatNumber"], HasError, validate.ErrExcluded)
+	c.Assert(errs["VatNumber"], HasError, validate.ErrMin)
+
+	//should pass
+	err = validate.ValidateAll(test[2])
+	c.Assert(err, IsNil)
+
+	err = validate.ValidateAll(test[3])
+	c.Assert(err, IsNil)
+
+	//error, unexported fields can not be referred to
+	test2 := struct {
+		A      string `validate:"required_with(secret)"`
+		B      string `validate:"required_if(secret,x)"`
+		secret string
+	}{secret: "x"}
+	err = validate.ValidateAll(test2)
+	c.Assert(err, NotNil)
+
+	errs, ok = err.(validate.Errors)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 2)
+	c.Assert(errs["A"], HasError, validate.ErrBadParameter)
+	c.Assert(errs["B"], HasError, validate.ErrBadParameter)
+}
+
 func (vs *ValidatorSuite) TestValidateAllIgnoreNonExportedVars(c *C) {
 	var test = []struct {
 		A int `validate:"required"`
@@ -478,6 +915,441 @@ func (vs *ValidatorSuite) TestValidateAllWithCustomValidator(c *C) {
 	c.Assert(errs, HasError, validate.ErrInvalid)
 }
 
+func (vs *ValidatorSuite) TestValidateAllWithContextValidator(c *C) {
+	type testInner struct {
+		Tags []string `json:"tags" validate:"dive;notfoo(x)"`
+	}
+
+	test := struct {
+		Name  string    `json:"name" validate:"notfoo"`
+		Inner testInner `json:"inner"`
+	}{
+		Name:  "foo",
+		Inner: testInner{Tags: []string{"bar", "foo"}},
+	}
+
+	var contexts []*validate.FieldContext
+	validator := validate.NewValidator(validate.NameResolverOption(validate.JsonNameResolver))
+	err := validator.SetContextValidationFunc("notfoo", func(fc *validate.FieldContext) error {
+		contexts = append(contexts, fc)
+		if fc.Value == "foo" {
+			return validate.ErrInvalid
+		}
+		return nil
+	})
+	c.Assert(err, IsNil)
+
+	err = validator.ValidateAll(test)
+	c.Assert(err, NotNil)
+
+	errs, ok := err.(validate.Errors)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 2)
+	c.Assert(errs["name"], HasError, validate.ErrInvalid)
+	c.Assert(errs["inner.tags.1"], HasError, validate.ErrInvalid)
+
+	c.Assert(contexts, HasLen, 3)
+	c.Assert(contexts[0].Context, NotNil)
+	c.Assert(contexts[0].Name, Equals, "name")
+	c.Assert(contexts[0].Path, Equals, "name")
+	c.Assert(contexts[0].Field.Name, Equals, "Name")
+	c.Assert(contexts[0].Tag, Equals, "notfoo")
+	c.Assert(contexts[0].Params, HasLen, 0)
+	c.Assert(contexts[0].Parent.Field(0).Interface(), Equals, "foo")
+	c.Assert(contexts[2].Value, Equals, "foo")
+	c.Assert(contexts[2].Name, Equals, "tags")
+	c.Assert(contexts[2].Path, Equals, "inner.tags.1")
+	c.Assert(contexts[2].Field.Name, Equals, "Tags")
+	c.Assert(contexts[2].Tag, Equals, "dive;notfoo(x)")
+	c.Assert(contexts[2].Params, DeepEquals, []string{"x"})
+	c.Assert(contexts[2].Parent.Type(), Equals, reflect.TypeOf(testInner{}))
+
+	err = validator.ValidAll("foo", "notfoo")
+	c.Assert(err, NotNil)
+	c.Assert(contexts[3].Tag, Equals, "notfoo")
+	c.Assert(contexts[3].Parent.IsValid(), Equals, false)
+}
+
+type testCtxKey string
+
+type testStructValidateCtxInterface struct {
+	A int `validate:"min(2)"`
+}
+
+func (s testStructValidateCtxInterface) Validate() error {
+	return validate.ErrInvalid
+}
+
+func (s testStructValidateCtxInterface) ValidateCtx(ctx context.Context) error {
+	return ctx.Value(testCtxKey("err")).(error)
+}
+
+func (vs *ValidatorSuite) TestValidateAllCtx(c *C) {
+	test := struct {
+		A string `validate:"ctxvalue"`
+		B string `validate:"ctxvalue"`
+		C testStructValidateCtxInterface
+	}{}
+
+	var values []interface{}
+	validator := validate.NewValidator()
+	validator.SetContextValidationFunc("ctxvalue", func(fc *validate.FieldContext) error {
+		values = append(values, fc.Context.Value(testCtxKey("key")))
+		return nil
+	})
+
+	ctx := context.WithValue(context.Background(), testCtxKey("key"), "foo")
+	ctx = context.WithValue(ctx, testCtxKey("err"), validate.ErrEmpty)
+	err := validator.ValidateAllCtx(ctx, test)
+	c.Assert(err, NotNil)
+
+	errs, ok := err.(validate.Errors)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 2)
+	c.Assert(errs["C.A"], HasError, validate.ErrMin)
+	c.Assert(errs["C._"], HasError, validate.ErrEmpty)
+	c.Assert(values, DeepEquals, []interface{}{"foo", "foo"})
+
+	err = validator.ValidCtx(ctx, "bar", "ctxvalue")
+	c.Assert(err, IsNil)
+	c.Assert(values, HasLen, 3)
+}
+
+func (vs *ValidatorSuite) TestValidateAllCtxCancelled(c *C) {
+	test := struct {
+		A []string `validate:"dive;cancel"`
+		B string   `validate:"cancel"`
+	}{A: []string{"a", "b"}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	validator := validate.NewValidator()
+	validator.SetContextValidationFunc("cancel", func(fc *validate.FieldContext) error {
+		calls++
+		cancel()
+		return nil
+	})
+
+	err := validator.ValidateAllCtx(ctx, test)
+	c.Assert(err, Equals, context.Canceled)
+	c.Assert(calls, Equals, 1)
+
+	err = validator.ValidateCtx(ctx, test)
+	c.Assert(err, Equals, context.Canceled)
+	c.Assert(calls, Equals, 1)
+
+	err = validator.ValidAllCtx(ctx, "a", "cancel")
+	c.Assert(err, Equals, context.Canceled)
+	c.Assert(calls, Equals, 1)
+}
+
+func (vs *ValidatorSuite) TestValidateAllGroups(c *C) {
+	type testUser struct {
+		ID       int    `validate:"group(update,import);required"`
+		Username string `validate:"group(create);required;endgroup;max(8)"`
+		Password string `validate:"group(create);required;group(update);omitempty;endgroup;min(6)"`
+	}
+
+	test := testUser{Username: "foobarbaz"}
+
+	//error, no groups only validates rules outside of groups
+	err := validate.ValidateAll(test)
+	c.Assert(err, NotNil)
+
+	errs, ok := err.(validate.Errors)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 2)
+	c.Assert(errs["Username"], HasLen, 1)
+	c.Assert(errs["Username"], HasError, validate.ErrMax)
+	c.Assert(errs["Password"], HasLen, 1)
+	c.Assert(errs["Password"], HasError, validate.ErrMin)
+
+	//error, create group
+	err = validate.ValidateAllCtx(validate.WithGroups(context.Background(), "create"), test)
+	c.Assert(err, NotNil)
+
+	errs, ok = err.(validate.Errors)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 2)
+	c.Assert(errs["Username"], HasLen, 1)
+	c.Assert(errs["Password"], HasLen, 2)
+	c.Assert(errs["Password"], HasError, validate.ErrRequired)
+	c.Assert(errs["Password"], HasError, validate.ErrMin)
+
+	//error, update group
+	err = validate.ValidateAllCtx(validate.WithGroups(context.Background(), "update"), test)
+	c.Assert(err, NotNil)
+
+	errs, ok = err.(validate.Errors)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 2)
+	c.Assert(errs["ID"], HasLen, 1)
+	c.Assert(errs["ID"], HasError, validate.ErrRequired)
+	c.Assert(errs["Username"], HasLen, 1)
+
+	//error, multiple groups
+	err = validate.ValidateAllCtx(validate.WithGroups(context.Background(), "import", "create"), test)
+	c.Assert(err, NotNil)
+
+	errs, ok = err.(validate.Errors)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 3)
+	c.Assert(errs["ID"], HasError, validate.ErrRequired)
+	c.Assert(errs["Password"], HasError, validate.ErrRequired)
+
+	//should pass
+	test = testUser{ID: 1, Username: "foo"}
+	err = validate.ValidateAllCtx(validate.WithGroups(context.Background(), "update"), test)
+	c.Assert(err, IsNil)
+
+	err = validate.ValidCtx(validate.WithGroups(context.Background(), "update"), "", "group(create);required")
+	c.Assert(err, IsNil)
+
+	err = validate.ValidCtx(validate.WithGroups(context.Background(), "create"), "", "group(create);required")
+	c.Assert(err, NotNil)
+}
+
+func (vs *ValidatorSuite) TestValidGroupsSyntaxError(c *C) {
+	err := validate.ValidAll("", "required;endgroup")
+	c.Assert(err, Equals, validate.ErrSyntax)
+
+	err = validate.ValidAll("", "group;required")
+	c.Assert(err, Equals, validate.ErrInvalidParameterCount)
+}
+
+type testPatchAddress struct {
+	Street string `json:"street" validate:"required"`
+	City   string `json:"city" validate:"required"`
+}
+
+type testPatch struct {
+	Name      string                      `json:"name" validate:"required"`
+	Email     string                      `json:"email" validate:"required"`
+	Address   *testPatchAddress           `json:"address" validate:"required"`
+	Phones    []string                    `json:"phones" validate:"min(1);dive;min(5)"`
+	Addresses map[string]testPatchAddress `json:"addresses"`
+}
+
+func (vs *ValidatorSuite) TestValidatePartial(c *C) {
+	test := testPatch{
+		Address:   &testPatchAddress{},
+		Phones:    []string{"1", "2"},
+		Addresses: map[string]testPatchAddress{"home": {}, "work": {Street: "x"}},
+	}
+
+	err := validate.ValidatePartial(test, "Name", "Address.Street", "Phones.1", "Addresses.*.City")
+	c.Assert(err, NotNil)
+
+	errs, ok := err.(validate.Errors)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 5)
+	c.Assert(errs["Name"], HasError, validate.ErrRequired)
+	c.Assert(errs["Address.Street"], HasError, validate.ErrRequired)
+	c.Assert(errs["Phones.1"], HasError, validate.ErrMin)
+	c.Assert(errs["Addresses.home.City"], HasError, validate.ErrRequired)
+	c.Assert(errs["Addresses.work.City"], HasError, validate.ErrRequired)
+
+	// nothing selected
+	err = validate.ValidatePartial(test)
+	c.Assert(err, IsNil)
+
+	// a shorter path selects everything below
+	err = validate.ValidatePartial(test, "Address.Street", "Address")
+	c.Assert(err, NotNil)
+	c.Assert(err.(validate.Errors), HasLen, 2)
+
+	err = validate.ValidatePartial(test, "Address", "Address.Street")
+	c.Assert(err, NotNil)
+	c.Assert(err.(validate.Errors), HasLen, 2)
+
+	// duplicate paths
+	err = validate.ValidatePartial(test, "Name", "Name")
+	c.Assert(err, NotNil)
+	errs = err.(validate.Errors)
+	c.Assert(errs, HasLen, 1)
+	c.Assert(errs["Name"], HasError, validate.ErrRequired)
+
+	err = validate.ValidatePartial(test, "Address.Street", "Address.Street")
+	c.Assert(err, NotNil)
+	c.Assert(err.(validate.Errors), HasLen, 1)
+
+	// resolved names
+	validator := validate.NewValidator(validate.NameResolverOption(validate.JsonNameResolver))
+	err = validator.ValidatePartial(test, "email", "address.city")
+	c.Assert(err, NotNil)
+
+	errs = err.(validate.Errors)
+	c.Assert(errs, HasLen, 2)
+	c.Assert(errs["email"], HasError, validate.ErrRequired)
+	c.Assert(errs["address.city"], HasError, validate.ErrRequired)
+}
+
+func (vs *ValidatorSuite) TestValidateExcept(c *C) {
+	test := testPatch{
+		Address: &testPatchAddress{},
+		Phones:  []string{"1"},
+	}
+
+	err := validate.ValidateExcept(test, "Name", "Address.Street", "Phones.*")
+	c.Assert(err, NotNil)
+
+	errs, ok := err.(validate.Errors)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 2)
+	c.Assert(errs["Email"], HasError, validate.ErrRequired)
+	c.Assert(errs["Address.City"], HasError, validate.ErrRequired)
+
+	err = validate.ValidateAllCtx(validate.WithoutPaths(context.Background(), "Name", "Email", "Address", "Phones"), test)
+	c.Assert(err, IsNil)
+}
+
+func (vs *ValidatorSuite) TestValidateAllFieldError(c *C) {
+	type testAddress struct {
+		Street string `json:"street" validate:"min(3)"`
+	}
+
+	test := struct {
+		Addresses map[string]testAddress `json:"addresses"`
+	}{
+		Addresses: map[string]testAddress{"home": {Street: "ab"}},
+	}
+
+	validator := validate.NewValidator(validate.NameResolverOption(validate.JsonNameResolver))
+	err := validator.ValidateAll(test)
+	c.Assert(err, NotNil)
+
+	errs, ok := err.(validate.Errors)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["addresses.home.street"], HasLen, 1)
+
+	var fieldErr *validate.FieldError
+	c.Assert(errors.As(errs["addresses.home.street"][0], &fieldErr), Equals, true)
+	c.Assert(fieldErr.Code, Equals, "min")
+	c.Assert(fieldErr.Params, DeepEquals, []string{"3"})
+	c.Assert(fieldErr.Path, Equals, "Addresses.home.Street")
+	c.Assert(fieldErr.Name, Equals, "addresses.home.street")
+	c.Assert(fieldErr.Value, Equals, "ab")
+	c.Assert(errors.Is(fieldErr, validate.ErrMin), Equals, true)
+	c.Assert(fieldErr.Error(), Equals, "less than min")
+
+	err = validate.ValidAll("ab", "min(3)")
+	c.Assert(err, NotNil)
+
+	errList, ok := err.(validate.ErrorList)
+	c.Assert(ok, Equals, true)
+	c.Assert(errList, HasLen, 1)
+	c.Assert(errors.As(errList[0], &fieldErr), Equals, true)
+	c.Assert(fieldErr.Code, Equals, "min")
+	c.Assert(fieldErr.Path, Equals, "")
+	c.Assert(fieldErr.Value, Equals, "ab")
+}
+
+func (vs *ValidatorSuite) TestValidateFieldErrorParamsAreCopies(c *C) {
+	type test struct {
+		Role string `validate:"in(admin,user)"`
+	}
+
+	validator := validate.NewValidator()
+	err := validator.Validate(test{Role: "guest"})
+	c.Assert(err, NotNil)
+
+	var fieldErr *validate.FieldError
+	c.Assert(errors.As(err.(validate.Errors)["Role"][0], &fieldErr), Equals, true)
+	fieldErr.Params[0] = "guest"
+
+	c.Assert(validator.Validate(test{Role: "admin"}), IsNil)
+	c.Assert(validator.Validate(test{Role: "guest"}), NotNil)
+}
+
+func (vs *ValidatorSuite) TestValidateAllBadParameterFailsCompilation(c *C) {
+	err := validate.ValidateAll(struct {
+		A string `validate:"min(abc)"`
+	}{A: "foo"})
+	c.Assert(err, Equals, validate.ErrBadParameter)
+
+	// params that cannot be compared with the kind of the field
+	c.Assert(validate.ValidateAll(struct {
+		A int `validate:"min(1.5)"`
+	}{A: 2}), Equals, validate.ErrBadParameter)
+
+	c.Assert(validate.ValidateAll(struct {
+		A uint `validate:"between(-1,5)"`
+	}{A: 2}), Equals, validate.ErrBadParameter)
+
+	c.Assert(validate.ValidateAll(struct {
+		A []int `validate:"max(2.5)"`
+	}{}), Equals, validate.ErrBadParameter)
+
+	c.Assert(validate.ValidateAll(struct {
+		A map[uint]int `validate:"keys;min(-1);endkeys"`
+	}{}), Equals, validate.ErrBadParameter)
+
+	c.Assert(validate.ValidateAll(struct {
+		A []uint `validate:"dive;max(-1)"`
+	}{}), Equals, validate.ErrBadParameter)
+
+	c.Assert(validate.ValidateAll(struct {
+		A float64     `validate:"min(1.5)"`
+		B uint        `validate:"max(5)"`
+		C interface{} `validate:"omitempty;min(1.5)"`
+	}{A: 2, B: 2}), IsNil)
+
+	err = validate.ValidateAll(struct {
+		A string `validate:"regexp(\"ab(cd\")"`
+	}{})
+	c.Assert(err, Equals, validate.ErrBadParameter)
+
+	err = validate.ValidateAll(struct {
+		A int `validate:"between(1)"`
+	}{})
+	c.Assert(err, Equals, validate.ErrInvalidParameterCount)
+}
+
+func (vs *ValidatorSuite) TestValidateAllWithValidationCompiler(c *C) {
+	compiled := 0
+	validator := validate.NewValidator(validate.CompilerOption("prefix", func(params []string) (validate.ValidatorFunc, error) {
+		compiled++
+		if len(params) != 1 {
+			return nil, validate.ErrInvalidParameterCount
+		}
+
+		prefix := params[0]
+		return func(v interface{}, _ []string) error {
+			if s, ok := v.(string); !ok || len(s) < len(prefix) || s[:len(prefix)] != prefix {
+				return validate.ErrInvalid
+			}
+			return nil
+		}, nil
+	}))
+
+	type testPrefix struct {
+		A string `validate:"prefix(foo)"`
+	}
+
+	for i := 0; i < 3; i++ {
+		err := validator.ValidateAll(testPrefix{A: "foobar"})
+		c.Assert(err, IsNil)
+
+		err = validator.ValidateAll(testPrefix{A: "bar"})
+		c.Assert(err, NotNil)
+		c.Assert(err.(validate.Errors)["A"], HasError, validate.ErrInvalid)
+	}
+	c.Assert(compiled, Equals, 1)
+
+	err := validator.ValidAll("foo", "prefix")
+	c.Assert(err, Equals, validate.ErrInvalidParameterCount)
+
+	// a validation func replaces the compiler
+	err = validator.SetValidationFunc("min", func(_ interface{}, _ []string) error {
+		return validate.ErrInvalid
+	})
+	c.Assert(err, IsNil)
+
+	err = validator.ValidAll("foo", "min(abc)")
+	c.Assert(err, NotNil)
+	c.Assert(err.(validate.ErrorList), HasError, validate.ErrInvalid)
+}
+
 func (vs *ValidatorSuite) TestValidateAllUnsetValidator(c *C) {
 
 	validator := validate.NewValidator()
@@ -568,7 +1440,7 @@ func (c *hasErrorChecker) Check(params []interface{}, names []string) (bool, str
 	}
 
 	for _, v := range slice {
-		if v == value {
+		if errors.Is(v, value) {
 			return true, ""
 		}
 	}